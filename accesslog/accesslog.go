@@ -0,0 +1,193 @@
+// Package accesslog provides a middleware that logs requests to http.Handlers built by denco.Mux.
+//
+//	logger := accesslog.New(accesslog.Slog(slog.Default()))
+//	logger.Params = []string{"id"}
+//	logger.RedactParams = []string{"token"}
+//	handler, _ := mux.Build(handlers)
+//	http.ListenAndServe(":8080", logger.Middleware(handler))
+package accesslog
+
+import (
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/internal/httputil"
+)
+
+// Redacted is the value that replaces the values of redacted path parameters.
+const Redacted = "[REDACTED]"
+
+// Entry represents an entry of the access log.
+type Entry struct {
+	// Time is the time when the request arrived.
+	Time time.Time
+
+	Method string
+	Host   string
+
+	// Path is the path of the request. The values of path parameters that are
+	// specified by Logger.RedactParams are replaced with Redacted.
+	Path  string
+	Proto string
+
+	// Route is the routing path of the matched route such as "/user/:id".
+	// It is empty if no route matched.
+	Route string
+
+	// Params is the path parameters that are specified by Logger.Params.
+	Params denco.Params
+
+	Status   int
+	Bytes    int64
+	Duration time.Duration
+
+	RemoteAddr string
+	User       string
+	Referer    string
+	UserAgent  string
+}
+
+// Logger logs requests.
+type Logger struct {
+	// Log is called with the entry of each request that is sampled.
+	Log func(e *Entry)
+
+	// Params is the names of path parameters that are logged.
+	Params []string
+
+	// RedactParams is the names of path parameters whose values are replaced with Redacted
+	// in Entry.Path and Entry.Params.
+	RedactParams []string
+
+	// Redact is called with the path of the request after RedactParams is applied.
+	// It can be used to mask secrets that cannot be identified by path parameters.
+	// If nil, the path is logged as is.
+	Redact func(path string) string
+
+	// SampleRate is the fraction of requests to be logged in range of (0, 1].
+	// Requests that result in server errors (5xx) are always logged.
+	// If zero, all requests are logged.
+	SampleRate float64
+
+	now    func() time.Time
+	random func() float64
+}
+
+// New returns a new Logger that calls log with each entry.
+func New(log func(e *Entry)) *Logger {
+	return &Logger{
+		Log:    log,
+		now:    time.Now,
+		random: rand.Float64,
+	}
+}
+
+// Middleware returns a http.Handler that logs requests to next.
+func (l *Logger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, route := denco.TrackRoute(r)
+		start := l.now()
+		rw := httputil.NewResponseWriter(w)
+		next.ServeHTTP(rw, r)
+		status := rw.StatusCode()
+		if !l.sampled(status) {
+			return
+		}
+		user, _, _ := r.BasicAuth()
+		e := &Entry{
+			Time:       start,
+			Method:     r.Method,
+			Host:       r.Host,
+			Path:       l.path(r, route),
+			Proto:      r.Proto,
+			Route:      route.Path,
+			Params:     l.params(route.Params),
+			Status:     status,
+			Bytes:      rw.Bytes,
+			Duration:   l.now().Sub(start),
+			RemoteAddr: r.RemoteAddr,
+			User:       user,
+			Referer:    r.Referer(),
+			UserAgent:  r.UserAgent(),
+		}
+		l.Log(e)
+	})
+}
+
+func (l *Logger) sampled(status int) bool {
+	if l.SampleRate <= 0 || l.SampleRate >= 1 || status >= 500 {
+		return true
+	}
+	return l.random() < l.SampleRate
+}
+
+func (l *Logger) redacted(name string) bool {
+	for _, n := range l.RedactParams {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// path returns the path of r that the values of redacted path parameters are replaced.
+func (l *Logger) path(r *http.Request, route *denco.Route) string {
+	path := r.URL.Path
+	for _, param := range route.Params {
+		if l.redacted(param.Name) {
+			path = redactPath(route.Path, route.Params, l.redacted)
+			break
+		}
+	}
+	if l.Redact != nil {
+		path = l.Redact(path)
+	}
+	return path
+}
+
+func (l *Logger) params(params denco.Params) denco.Params {
+	var result denco.Params
+	for _, name := range l.Params {
+		for _, param := range params {
+			if param.Name != name {
+				continue
+			}
+			if l.redacted(name) {
+				param.Value = Redacted
+			}
+			result = append(result, param)
+			break
+		}
+	}
+	return result
+}
+
+// redactPath builds the path from the routing path and params.
+// The values of path parameters that redacted reports true are replaced with Redacted.
+func redactPath(routePath string, params denco.Params, redacted func(name string) bool) string {
+	var b strings.Builder
+	for i := 0; i < len(routePath); i++ {
+		c := routePath[i]
+		if c != denco.ParamCharacter && c != denco.WildcardCharacter {
+			b.WriteByte(c)
+			continue
+		}
+		end := len(routePath)
+		if c == denco.ParamCharacter {
+			if j := strings.IndexByte(routePath[i:], '/'); j >= 0 {
+				end = i + j
+			}
+		}
+		name := routePath[i+1 : end]
+		if redacted(name) {
+			b.WriteString(Redacted)
+		} else {
+			b.WriteString(params.Get(name))
+		}
+		i = end - 1
+	}
+	return b.String()
+}
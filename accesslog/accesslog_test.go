@@ -0,0 +1,172 @@
+package accesslog
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/naoina/denco"
+)
+
+func newTestHandler(t *testing.T) http.Handler {
+	mux := denco.NewMux()
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/user/:id/reset/:token", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			fmt.Fprint(w, "reset")
+		}),
+		mux.GET("/static/*filepath", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			fmt.Fprint(w, params.Get("filepath"))
+		}),
+		mux.GET("/error", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			http.Error(w, "error", http.StatusInternalServerError)
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return handler
+}
+
+func newTestLogger(entries *[]Entry) *Logger {
+	l := New(func(e *Entry) {
+		*entries = append(*entries, *e)
+	})
+	now := time.Date(2014, 1, 6, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time {
+		now = now.Add(10 * time.Millisecond)
+		return now
+	}
+	return l
+}
+
+func TestLogger(t *testing.T) {
+	var entries []Entry
+	l := newTestLogger(&entries)
+	l.Params = []string{"id", "token", "filepath"}
+	l.RedactParams = []string{"token"}
+	h := l.Middleware(newTestHandler(t))
+
+	req := httptest.NewRequest("GET", "/user/42/reset/secret?q=1", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.SetBasicAuth("naoina", "password")
+	req.Header.Set("Referer", "http://example.com/")
+	req.Header.Set("User-Agent", "test")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/static/css/main.css", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/missing", nil))
+
+	expected := []Entry{
+		{
+			Time:       time.Date(2014, 1, 6, 0, 0, 0, 10*int(time.Millisecond), time.UTC),
+			Method:     "GET",
+			Host:       "example.com",
+			Path:       "/user/42/reset/[REDACTED]",
+			Proto:      "HTTP/1.1",
+			Route:      "/user/:id/reset/:token",
+			Params:     denco.Params{{Name: "id", Value: "42"}, {Name: "token", Value: "[REDACTED]"}},
+			Status:     200,
+			Bytes:      5,
+			Duration:   10 * time.Millisecond,
+			RemoteAddr: "192.0.2.1:1234",
+			User:       "naoina",
+			Referer:    "http://example.com/",
+			UserAgent:  "test",
+		},
+		{
+			Time:       time.Date(2014, 1, 6, 0, 0, 0, 30*int(time.Millisecond), time.UTC),
+			Method:     "GET",
+			Host:       "example.com",
+			Path:       "/static/css/main.css",
+			Proto:      "HTTP/1.1",
+			Route:      "/static/*filepath",
+			Params:     denco.Params{{Name: "filepath", Value: "css/main.css"}},
+			Status:     200,
+			Bytes:      12,
+			Duration:   10 * time.Millisecond,
+			RemoteAddr: "192.0.2.1:1234",
+		},
+		{
+			Time:       time.Date(2014, 1, 6, 0, 0, 0, 50*int(time.Millisecond), time.UTC),
+			Method:     "POST",
+			Host:       "example.com",
+			Path:       "/missing",
+			Proto:      "HTTP/1.1",
+			Status:     404,
+			Bytes:      19,
+			Duration:   10 * time.Millisecond,
+			RemoteAddr: "192.0.2.1:1234",
+		},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("entries => %#v, want %#v", entries, expected)
+	}
+}
+
+func TestLogger_Redact(t *testing.T) {
+	var entries []Entry
+	l := newTestLogger(&entries)
+	l.Redact = func(path string) string {
+		return path[:len("/static/")] + "..."
+	}
+	l.Middleware(newTestHandler(t)).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/static/private/key", nil))
+	if len(entries) != 1 || entries[0].Path != "/static/..." {
+		t.Errorf("entries => %#v, want an entry with path %q", entries, "/static/...")
+	}
+}
+
+func TestLogger_SampleRate(t *testing.T) {
+	var entries []Entry
+	l := newTestLogger(&entries)
+	l.SampleRate = 0.5
+	randoms := []float64{0.9, 0.1, 0.9}
+	l.random = func() float64 {
+		r := randoms[0]
+		randoms = randoms[1:]
+		return r
+	}
+	h := l.Middleware(newTestHandler(t))
+	for _, path := range []string{"/static/a", "/static/b", "/error", "/static/c"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	var actual []string
+	for _, e := range entries {
+		actual = append(actual, e.Path)
+	}
+	if expected := []string{"/static/b", "/error"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("sampled paths => %#v, want %#v", actual, expected)
+	}
+}
+
+func TestCombined(t *testing.T) {
+	var buf bytes.Buffer
+	log := Combined(&buf)
+	log(&Entry{
+		Time:       time.Date(2014, 1, 6, 12, 34, 56, 0, time.FixedZone("JST", 9*60*60)),
+		Method:     "GET",
+		Path:       "/user/42",
+		Proto:      "HTTP/1.1",
+		Status:     200,
+		Bytes:      5,
+		RemoteAddr: "192.0.2.1:1234",
+		User:       "naoina",
+		Referer:    "http://example.com/",
+		UserAgent:  `agent "quoted"`,
+	})
+	log(&Entry{
+		Time:       time.Date(2014, 1, 6, 12, 34, 56, 0, time.UTC),
+		Method:     "POST",
+		Path:       "/missing",
+		Proto:      "HTTP/1.1",
+		Status:     404,
+		RemoteAddr: "192.0.2.1",
+	})
+	expected := `192.0.2.1 - naoina [06/Jan/2014:12:34:56 +0900] "GET /user/42 HTTP/1.1" 200 5 "http://example.com/" "agent \"quoted\""` + "\n" +
+		`192.0.2.1 - - [06/Jan/2014:12:34:56 +0000] "POST /missing HTTP/1.1" 404 - "-" "-"` + "\n"
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Combined => %q, want %q", actual, expected)
+	}
+}
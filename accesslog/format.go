@@ -0,0 +1,54 @@
+package accesslog
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CombinedTimeFormat is the time format of Apache combined log format.
+const CombinedTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// Combined returns a function for Logger.Log that writes entries to w in Apache combined log format.
+// Writes to w are serialized.
+func Combined(w io.Writer) func(e *Entry) {
+	var mu sync.Mutex
+	return func(e *Entry) {
+		line := FormatCombined(e)
+		mu.Lock()
+		defer mu.Unlock()
+		io.WriteString(w, line)
+	}
+}
+
+// FormatCombined returns a line of e in Apache combined log format that ends with a newline.
+func FormatCombined(e *Entry) string {
+	host := e.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	bytes := "-"
+	if e.Bytes > 0 {
+		bytes = strconv.FormatInt(e.Bytes, 10)
+	}
+	return fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s \"%s\" \"%s\"\n",
+		orDash(host), orDash(e.User), e.Time.Format(CombinedTimeFormat),
+		e.Method, escape(e.Path), e.Proto, e.Status, bytes,
+		orDash(escape(e.Referer)), orDash(escape(e.UserAgent)))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
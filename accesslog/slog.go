@@ -0,0 +1,37 @@
+//go:build go1.21
+
+package accesslog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Slog returns a function for Logger.Log that emits entries as records of logger.
+// Server errors (5xx) are logged at slog.LevelError, and other requests are logged at slog.LevelInfo.
+func Slog(logger *slog.Logger) func(e *Entry) {
+	return func(e *Entry) {
+		level := slog.LevelInfo
+		if e.Status >= 500 {
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{
+			slog.String("method", e.Method),
+			slog.String("host", e.Host),
+			slog.String("path", e.Path),
+			slog.String("route", e.Route),
+			slog.Int("status", e.Status),
+			slog.Int64("bytes", e.Bytes),
+			slog.Duration("duration", e.Duration),
+			slog.String("remote_addr", e.RemoteAddr),
+		}
+		if len(e.Params) > 0 {
+			params := make([]interface{}, len(e.Params))
+			for i, param := range e.Params {
+				params[i] = slog.String(param.Name, param.Value)
+			}
+			attrs = append(attrs, slog.Group("params", params...))
+		}
+		logger.LogAttrs(context.Background(), level, "request", attrs...)
+	}
+}
@@ -0,0 +1,42 @@
+//go:build go1.21
+
+package accesslog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/naoina/denco"
+)
+
+func TestSlog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	log := Slog(logger)
+	log(&Entry{
+		Method:     "GET",
+		Host:       "example.com",
+		Path:       "/user/42",
+		Route:      "/user/:id",
+		Params:     denco.Params{{Name: "id", Value: "42"}},
+		Status:     200,
+		Bytes:      5,
+		Duration:   10 * time.Millisecond,
+		RemoteAddr: "192.0.2.1:1234",
+	})
+	log(&Entry{Method: "GET", Path: "/error", Status: 500})
+	expected := `level=INFO msg=request method=GET host=example.com path=/user/42 route=/user/:id status=200 bytes=5 duration=10ms remote_addr=192.0.2.1:1234 params.id=42` + "\n" +
+		`level=ERROR msg=request method=GET host="" path=/error route="" status=500 bytes=0 duration=0s remote_addr=""` + "\n"
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Slog => %q, want %q", actual, expected)
+	}
+}
@@ -0,0 +1,116 @@
+// Package auth provides authentication middlewares for handlers of denco.Mux.
+//
+// The authenticated Principal is attached to the context of the request.
+//
+//	admin := auth.Require(auth.NewBasic("admin", map[string]string{"alice": "secret"}))
+//	api := auth.Require(&auth.Bearer{Verify: verifyToken}, &auth.HMAC{Key: lookupKey})
+//	handlers := mux.Wrap(admin.Wrap,
+//		mux.GET("/admin/users", listUsers),
+//		mux.POST("/admin/users", createUser),
+//	)
+//	handlers = append(handlers, mux.GET("/api/jobs", listJobs).Wrap(api.Wrap))
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/naoina/denco"
+)
+
+var (
+	// ErrNoCredentials is returned by Authenticator if the request has no credentials for it.
+	ErrNoCredentials = errors.New("auth: no credentials")
+
+	// ErrInvalidCredentials is returned by Authenticator if the credentials are invalid.
+	ErrInvalidCredentials = errors.New("auth: invalid credentials")
+)
+
+// Principal represents an authenticated client.
+type Principal struct {
+	// Name identifies the client such as a user name or a key ID.
+	Name string
+
+	// Scheme is the authentication scheme such as "Basic".
+	Scheme string
+
+	// Claims is arbitrary information about the client set by Authenticator.
+	Claims map[string]interface{}
+}
+
+type principalContextKey struct{}
+
+// WithPrincipal returns a copy of ctx that has p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// PrincipalFromContext returns the Principal that is authenticated for the request.
+// PrincipalFromContext returns nil if ctx has no Principal.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return p
+}
+
+// Authenticator authenticates requests.
+type Authenticator interface {
+	// Authenticate returns the Principal of r.
+	// Authenticate returns ErrNoCredentials if r has no credentials for the Authenticator.
+	Authenticate(r *http.Request) (*Principal, error)
+
+	// Challenge returns the value of WWW-Authenticate header such as `Basic realm="admin"`.
+	// The empty string means no challenge.
+	Challenge() string
+}
+
+// Middleware requires requests to be authenticated by one of Authenticators.
+type Middleware struct {
+	Authenticators []Authenticator
+
+	// Unauthorized is called when the request isn't authenticated with the error of the last Authenticator.
+	// If nil, the response is "401 Unauthorized" with WWW-Authenticate headers of the Authenticators.
+	Unauthorized func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// Require returns a new Middleware that authenticates requests by auths in order.
+// The first Authenticator that the request has credentials for decides the result.
+func Require(auths ...Authenticator) *Middleware {
+	return &Middleware{Authenticators: auths}
+}
+
+// Wrap returns a HandlerFunc that calls fn only if the request is authenticated.
+func (m *Middleware) Wrap(fn denco.HandlerFunc) denco.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		p, err := m.authenticate(r)
+		if err != nil {
+			m.unauthorized(w, r, err)
+			return
+		}
+		fn(w, r.WithContext(WithPrincipal(r.Context(), p)), params)
+	}
+}
+
+func (m *Middleware) authenticate(r *http.Request) (*Principal, error) {
+	err := ErrNoCredentials
+	for _, a := range m.Authenticators {
+		var p *Principal
+		if p, err = a.Authenticate(r); err != ErrNoCredentials {
+			return p, err
+		}
+	}
+	return nil, err
+}
+
+func (m *Middleware) unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	if m.Unauthorized != nil {
+		m.Unauthorized(w, r, err)
+		return
+	}
+	for _, a := range m.Authenticators {
+		if c := a.Challenge(); c != "" {
+			w.Header().Add("WWW-Authenticate", c)
+		}
+	}
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
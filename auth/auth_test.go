@@ -0,0 +1,111 @@
+package auth_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/auth"
+)
+
+func principalHandlerFunc(w http.ResponseWriter, r *http.Request, params denco.Params) {
+	p := auth.PrincipalFromContext(r.Context())
+	fmt.Fprintf(w, "%s %s", p.Scheme, p.Name)
+}
+
+func verifyToken(ctx context.Context, token string) (*auth.Principal, error) {
+	if token != "valid-token" {
+		return nil, auth.ErrInvalidCredentials
+	}
+	return &auth.Principal{Name: "bot", Claims: map[string]interface{}{"scope": "read"}}, nil
+}
+
+func TestMiddleware(t *testing.T) {
+	mux := denco.NewMux()
+	admin := auth.Require(auth.NewBasic("admin", map[string]string{"alice": "secret"}))
+	api := auth.Require(&auth.Bearer{Verify: verifyToken}, auth.NewBasic("api", map[string]string{"bob": "pass"}))
+	handlers := mux.Wrap(admin.Wrap,
+		mux.GET("/admin/users", principalHandlerFunc),
+		mux.POST("/admin/users", principalHandlerFunc),
+	)
+	handlers = append(handlers,
+		mux.GET("/api/jobs", principalHandlerFunc).Wrap(api.Wrap),
+		mux.GET("/public", testHandlerFunc),
+	)
+	handler, err := mux.Build(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		method, path  string
+		authorization string
+		status        int
+		body          string
+		challenges    []string
+	}{
+		{"GET", "/admin/users", basic("alice", "secret"), 200, "Basic alice", nil},
+		{"POST", "/admin/users", basic("alice", "secret"), 200, "Basic alice", nil},
+		{"GET", "/admin/users", basic("alice", "wrong"), 401, "Unauthorized\n", []string{`Basic realm="admin", charset="UTF-8"`}},
+		{"GET", "/admin/users", basic("mallory", "secret"), 401, "Unauthorized\n", []string{`Basic realm="admin", charset="UTF-8"`}},
+		{"GET", "/admin/users", "", 401, "Unauthorized\n", []string{`Basic realm="admin", charset="UTF-8"`}},
+		{"GET", "/api/jobs", "Bearer valid-token", 200, "Bearer bot", nil},
+		{"GET", "/api/jobs", "bearer valid-token", 200, "Bearer bot", nil},
+		{"GET", "/api/jobs", basic("bob", "pass"), 200, "Basic bob", nil},
+		{"GET", "/api/jobs", "Bearer invalid", 401, "Unauthorized\n", []string{"Bearer", `Basic realm="api", charset="UTF-8"`}},
+		{"GET", "/api/jobs", "Bearerish valid-token", 401, "Unauthorized\n", []string{"Bearer", `Basic realm="api", charset="UTF-8"`}},
+		{"GET", "/public", "", 200, "public", nil},
+	} {
+		req := httptest.NewRequest(v.method, v.path, nil)
+		if v.authorization != "" {
+			req.Header.Set("Authorization", v.authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != v.status || w.Body.String() != v.body {
+			t.Errorf("%s %s with %q => %v %q, want %v %q", v.method, v.path, v.authorization, w.Code, w.Body.String(), v.status, v.body)
+		}
+		if actual := w.Header()["Www-Authenticate"]; !reflect.DeepEqual(actual, v.challenges) {
+			t.Errorf("%s %s with %q => WWW-Authenticate %#v, want %#v", v.method, v.path, v.authorization, actual, v.challenges)
+		}
+	}
+}
+
+func TestMiddleware_Unauthorized(t *testing.T) {
+	m := auth.Require(&auth.Bearer{Verify: verifyToken})
+	var err error
+	m.Unauthorized = func(w http.ResponseWriter, r *http.Request, e error) {
+		err = e
+		w.WriteHeader(http.StatusForbidden)
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+	w := httptest.NewRecorder()
+	m.Wrap(principalHandlerFunc)(w, req, nil)
+	if w.Code != http.StatusForbidden || err != auth.ErrInvalidCredentials {
+		t.Errorf("Unauthorized => %v, %v, want %v, %v", w.Code, err, http.StatusForbidden, auth.ErrInvalidCredentials)
+	}
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	if p := auth.PrincipalFromContext(context.Background()); p != nil {
+		t.Errorf("PrincipalFromContext(context.Background()) => %#v, want nil", p)
+	}
+	expected := &auth.Principal{Name: "alice"}
+	if actual := auth.PrincipalFromContext(auth.WithPrincipal(context.Background(), expected)); actual != expected {
+		t.Errorf("PrincipalFromContext => %#v, want %#v", actual, expected)
+	}
+}
+
+func testHandlerFunc(w http.ResponseWriter, r *http.Request, params denco.Params) {
+	fmt.Fprint(w, "public")
+}
+
+func basic(user, password string) string {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.SetBasicAuth(user, password)
+	return req.Header.Get("Authorization")
+}
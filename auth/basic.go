@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+)
+
+// Basic is an Authenticator of HTTP Basic authentication.
+type Basic struct {
+	// Realm is the realm of the challenge.
+	Realm string
+
+	// Verify reports whether the password of user is valid.
+	// Verify should compare the password in constant time.
+	Verify func(user, password string) bool
+}
+
+// NewBasic returns a new Basic that verifies passwords of users in constant time.
+// users is a map of user names to passwords.
+func NewBasic(realm string, users map[string]string) *Basic {
+	digests := make(map[string][sha256.Size]byte, len(users))
+	for user, password := range users {
+		digests[user] = sha256.Sum256([]byte(password))
+	}
+	return &Basic{
+		Realm: realm,
+		Verify: func(user, password string) bool {
+			// Compare the digest even if user doesn't exist, so that the time doesn't reveal existence of users.
+			expected, ok := digests[user]
+			actual := sha256.Sum256([]byte(password))
+			return subtle.ConstantTimeCompare(actual[:], expected[:]) == 1 && ok
+		},
+	}
+}
+
+// Authenticate implements Authenticator.
+func (b *Basic) Authenticate(r *http.Request) (*Principal, error) {
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+	if !b.Verify(user, password) {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{Name: user, Scheme: "Basic"}, nil
+}
+
+// Challenge implements Authenticator.
+func (b *Basic) Challenge() string {
+	return `Basic realm=` + strconv.Quote(b.Realm) + `, charset="UTF-8"`
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Bearer is an Authenticator of Bearer tokens of RFC 6750.
+type Bearer struct {
+	// Realm is the realm of the challenge. It is optional.
+	Realm string
+
+	// Verify returns the Principal of token.
+	// Verify should return ErrInvalidCredentials if token is invalid.
+	Verify func(ctx context.Context, token string) (*Principal, error)
+}
+
+// Authenticate implements Authenticator.
+func (b *Bearer) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := credentials(r, "Bearer")
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+	if token == "" {
+		return nil, ErrInvalidCredentials
+	}
+	p, err := b.Verify(r.Context(), token)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrInvalidCredentials
+	}
+	if p.Scheme == "" {
+		p.Scheme = "Bearer"
+	}
+	return p, nil
+}
+
+// Challenge implements Authenticator.
+func (b *Bearer) Challenge() string {
+	if b.Realm == "" {
+		return "Bearer"
+	}
+	return "Bearer realm=" + strconv.Quote(b.Realm)
+}
+
+// credentials returns the credentials of scheme in Authorization header of r.
+// The scheme is case-insensitive.
+func credentials(r *http.Request, scheme string) (string, bool) {
+	h := r.Header.Get("Authorization")
+	if len(h) < len(scheme) || !strings.EqualFold(h[:len(scheme)], scheme) {
+		return "", false
+	}
+	if len(h) == len(scheme) {
+		return "", true
+	}
+	if h[len(scheme)] != ' ' {
+		return "", false
+	}
+	return strings.TrimSpace(h[len(scheme)+1:]), true
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HMACScheme is the authentication scheme of HMAC.
+const HMACScheme = "HMAC-SHA256"
+
+// DefaultMaxSkew is the default value of HMAC.MaxSkew.
+const DefaultMaxSkew = 5 * time.Minute
+
+// DefaultMaxBodyBytes is the default value of HMAC.MaxBodyBytes.
+const DefaultMaxBodyBytes = 10 << 20
+
+// HMAC is an Authenticator of requests signed with HMAC-SHA256 by Sign.
+// The signature covers the method, the request URI, the host, the Date header and the body,
+// and is sent in Authorization header such as:
+//
+//	Authorization: HMAC-SHA256 keyId="client1", signature="base64 encoded signature"
+type HMAC struct {
+	// Key returns the secret key of keyID.
+	// Key should return ErrInvalidCredentials if keyID is unknown.
+	Key func(ctx context.Context, keyID string) ([]byte, error)
+
+	// MaxSkew is the maximum difference between the Date header and the current time.
+	// If zero, DefaultMaxSkew is used. If negative, the Date header isn't checked.
+	MaxSkew time.Duration
+
+	// MaxBodyBytes is the maximum size of the request body to read for verifying the signature.
+	// Requests that have a larger body are rejected with ErrInvalidCredentials.
+	// If zero, DefaultMaxBodyBytes is used. If negative, the size isn't limited.
+	MaxBodyBytes int64
+}
+
+// Authenticate implements Authenticator.
+// The body of r is read to verify the signature and is replaced with a copy of it.
+// The request URI is taken from r.RequestURI if it is set, so the signature is verified
+// against the request line that the client sent even if the path is rewritten by Mux.Mount.
+func (h *HMAC) Authenticate(r *http.Request) (*Principal, error) {
+	cred, ok := credentials(r, HMACScheme)
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+	params := parseAuthParams(cred)
+	keyID, sig := params["keyId"], params["signature"]
+	if keyID == "" || sig == "" {
+		return nil, ErrInvalidCredentials
+	}
+	signature, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if !h.validDate(r.Header.Get("Date")) {
+		return nil, ErrInvalidCredentials
+	}
+	key, err := h.Key(r.Context(), keyID)
+	if err != nil {
+		return nil, err
+	}
+	maxBodyBytes := h.MaxBodyBytes
+	if maxBodyBytes == 0 {
+		maxBodyBytes = DefaultMaxBodyBytes
+	}
+	expected, err := signature256(r, key, maxBodyBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(signature, expected) {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{Name: keyID, Scheme: HMACScheme}, nil
+}
+
+// Challenge implements Authenticator.
+func (h *HMAC) Challenge() string {
+	return HMACScheme
+}
+
+func (h *HMAC) validDate(date string) bool {
+	maxSkew := h.MaxSkew
+	if maxSkew < 0 {
+		return true
+	}
+	if maxSkew == 0 {
+		maxSkew = DefaultMaxSkew
+	}
+	t, err := http.ParseTime(date)
+	if err != nil {
+		return false
+	}
+	d := time.Since(t)
+	return -maxSkew <= d && d <= maxSkew
+}
+
+// Sign signs r with key for HMAC.
+// Sign sets the Date header to the current time if r has no Date header.
+// The body of r is read and is replaced with a copy of it.
+func Sign(r *http.Request, keyID string, key []byte) error {
+	if r.Header.Get("Date") == "" {
+		r.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	}
+	signature, err := signature256(r, key, -1)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Authorization", HMACScheme+" keyId="+strconv.Quote(keyID)+`, signature="`+base64.StdEncoding.EncodeToString(signature)+`"`)
+	return nil
+}
+
+// signature256 returns the HMAC-SHA256 signature of r.
+// signature256 returns ErrInvalidCredentials if the body of r is larger than maxBodyBytes.
+// If maxBodyBytes is negative, the size of the body isn't limited.
+func signature256(r *http.Request, key []byte, maxBodyBytes int64) ([]byte, error) {
+	var body []byte
+	if r.Body != nil {
+		var reader io.Reader = r.Body
+		if maxBodyBytes >= 0 {
+			reader = io.LimitReader(r.Body, maxBodyBytes+1)
+		}
+		var err error
+		if body, err = ioutil.ReadAll(reader); err != nil {
+			return nil, err
+		}
+		if maxBodyBytes >= 0 && int64(len(body)) > maxBodyBytes {
+			return nil, ErrInvalidCredentials
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	host := r.Host
+	if host == "" && r.URL != nil {
+		host = r.URL.Host
+	}
+	uri := r.RequestURI
+	if uri == "" {
+		uri = r.URL.RequestURI()
+	}
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join([]string{
+		r.Method,
+		uri,
+		host,
+		r.Header.Get("Date"),
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")))
+	return mac.Sum(nil), nil
+}
+
+// parseAuthParams parses comma separated auth-params such as `keyId="a", signature="b"`.
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for _, kv := range strings.Split(s, ",") {
+		i := strings.IndexByte(kv, '=')
+		if i < 0 {
+			continue
+		}
+		key, value := strings.TrimSpace(kv[:i]), strings.TrimSpace(kv[i+1:])
+		if v, err := strconv.Unquote(value); err == nil {
+			value = v
+		}
+		params[key] = value
+	}
+	return params
+}
@@ -0,0 +1,128 @@
+package auth_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/auth"
+)
+
+func TestHMAC(t *testing.T) {
+	keys := map[string][]byte{"client1": []byte("key1")}
+	h := &auth.HMAC{
+		Key: func(ctx context.Context, keyID string) ([]byte, error) {
+			key, ok := keys[keyID]
+			if !ok {
+				return nil, auth.ErrInvalidCredentials
+			}
+			return key, nil
+		},
+	}
+	fn := auth.Require(h).Wrap(func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		body, _ := ioutil.ReadAll(r.Body)
+		p := auth.PrincipalFromContext(r.Context())
+		fmt.Fprintf(w, "%s %s %s", p.Scheme, p.Name, body)
+	})
+	newRequest := func(body string) *http.Request {
+		return httptest.NewRequest("POST", "http://example.com/jobs?priority=high", strings.NewReader(body))
+	}
+	for _, v := range []struct {
+		name   string
+		req    func() *http.Request
+		status int
+		body   string
+	}{
+		{"valid", func() *http.Request {
+			req := newRequest(`{"name":"job"}`)
+			if err := auth.Sign(req, "client1", []byte("key1")); err != nil {
+				t.Fatal(err)
+			}
+			return req
+		}, 200, `HMAC-SHA256 client1 {"name":"job"}`},
+		{"tampered body", func() *http.Request {
+			req := newRequest(`{"name":"job"}`)
+			auth.Sign(req, "client1", []byte("key1"))
+			req.Body = ioutil.NopCloser(strings.NewReader(`{"name":"evil"}`))
+			return req
+		}, 401, "Unauthorized\n"},
+		{"tampered query", func() *http.Request {
+			req := newRequest("")
+			auth.Sign(req, "client1", []byte("key1"))
+			req.URL.RawQuery = "priority=low"
+			req.RequestURI = req.URL.RequestURI()
+			return req
+		}, 401, "Unauthorized\n"},
+		{"wrong key", func() *http.Request {
+			req := newRequest("")
+			auth.Sign(req, "client1", []byte("key2"))
+			return req
+		}, 401, "Unauthorized\n"},
+		{"unknown key", func() *http.Request {
+			req := newRequest("")
+			auth.Sign(req, "client2", []byte("key1"))
+			return req
+		}, 401, "Unauthorized\n"},
+		{"expired", func() *http.Request {
+			req := newRequest("")
+			req.Header.Set("Date", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+			auth.Sign(req, "client1", []byte("key1"))
+			return req
+		}, 401, "Unauthorized\n"},
+		{"no credentials", func() *http.Request {
+			return newRequest("")
+		}, 401, "Unauthorized\n"},
+	} {
+		w := httptest.NewRecorder()
+		fn(w, v.req(), nil)
+		if w.Code != v.status || w.Body.String() != v.body {
+			t.Errorf("%s => %v %q, want %v %q", v.name, w.Code, w.Body.String(), v.status, v.body)
+		}
+	}
+}
+
+func TestHMAC_mounted(t *testing.T) {
+	h := &auth.HMAC{
+		Key: func(ctx context.Context, keyID string) ([]byte, error) {
+			return []byte("key1"), nil
+		},
+		MaxBodyBytes: 16,
+	}
+	sub := denco.NewMux()
+	subHandler, err := sub.Build([]denco.Handler{
+		sub.POST("/jobs", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			fmt.Fprint(w, auth.PrincipalFromContext(r.Context()).Name)
+		}).Wrap(auth.Require(h).Wrap),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := denco.NewMux()
+	handler, err := mux.Build(mux.Mount("/api", subHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		body   string
+		status int
+	}{
+		{`{"name":"job"}`, 200},
+		{`{"name":"long job"}`, 401},
+	} {
+		req := httptest.NewRequest("POST", "/api/jobs?priority=high", strings.NewReader(v.body))
+		if err := auth.Sign(req, "client1", []byte("key1")); err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != v.status {
+			t.Errorf("POST %q with body %q => %v, want %v", "/api/jobs?priority=high", v.body, w.Code, v.status)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package binding fills structs from HTTP requests that routed by denco.
+//
+// The fields of a struct are filled from the path parameters, the query string,
+// the headers and the body of a request by the tags:
+//
+//	type CreateComment struct {
+//		PostID int64  `path:"id"`
+//		Page   int    `query:"page"`
+//		Token  string `header:"X-Token"`
+//		Body   string `json:"body" form:"body"`
+//	}
+//
+// The body is decoded as JSON if Content-Type is "application/json", or fields
+// that have `form' tag are filled from the form if Content-Type is
+// "application/x-www-form-urlencoded" or "multipart/form-data".
+package binding
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/internal/bind"
+)
+
+// DefaultMaxBodySize is a default maximum size of request body in bytes.
+const DefaultMaxBodySize = 10 << 20
+
+// Sources of values.
+const (
+	SourcePath   = "path"
+	SourceQuery  = "query"
+	SourceHeader = "header"
+	SourceForm   = "form"
+	SourceBody   = "body"
+)
+
+// Validator is the interface that is implemented by structs that validate themselves after binding.
+type Validator interface {
+	Validate() error
+}
+
+// Binder fills structs from HTTP requests.
+type Binder struct {
+	// MaxBodySize is a maximum size of request body in bytes.
+	// If zero, DefaultMaxBodySize will be used.
+	MaxBodySize int64
+
+	// Validate is called after binding if not nil, in addition to Validator.
+	// It can be used to integrate a validation library.
+	Validate func(v interface{}) error
+}
+
+// DefaultBinder is the Binder that used by Bind.
+var DefaultBinder = &Binder{}
+
+// Bind fills the struct that v points to from r and params by DefaultBinder.
+func Bind(r *http.Request, params denco.Params, v interface{}) error {
+	return DefaultBinder.Bind(r, params, v)
+}
+
+// Bind fills the struct that v points to from r and params.
+// The body is decoded first, and then the fields that have `path', `query' and
+// `header' tags are filled. `path' tag is the same as denco.Params.Bind, and
+// the field that has "required" option is reported as an error if the path parameter
+// isn't found. Finally, the struct is validated.
+// If there are invalid values, Bind returns *Error that contains all of them.
+func (b *Binder) Bind(r *http.Request, params denco.Params, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("binding: Bind requires a pointer to struct, but %T given", v)
+	}
+	fields, err := b.bindBody(r, v)
+	if err != nil {
+		return &Error{Err: err}
+	}
+	e := &Error{Fields: fields}
+	var query map[string][]string
+	e.Fields = append(e.Fields, bindFields(rv.Elem(), func(f reflect.StructField) (string, string, []string, bool) {
+		if tag, found := bind.LookupTag(f, bind.PathTag); found {
+			for _, p := range params {
+				if p.Name == tag.Name {
+					return SourcePath, tag.Name, []string{p.Value}, tag.Required
+				}
+			}
+			return SourcePath, tag.Name, nil, tag.Required
+		}
+		if name, found := f.Tag.Lookup(SourceQuery); found {
+			if query == nil {
+				query = r.URL.Query()
+			}
+			return SourceQuery, name, query[name], false
+		}
+		if name, found := f.Tag.Lookup(SourceHeader); found {
+			return SourceHeader, name, r.Header[http.CanonicalHeaderKey(name)], false
+		}
+		return "", "", nil, false
+	})...)
+	if len(e.Fields) > 0 {
+		return e
+	}
+	if err := b.validate(v); err != nil {
+		if ve, ok := err.(*Error); ok {
+			return ve
+		}
+		return &Error{Err: err}
+	}
+	return nil
+}
+
+func (b *Binder) validate(v interface{}) error {
+	if vv, ok := v.(Validator); ok {
+		if err := vv.Validate(); err != nil {
+			return err
+		}
+	}
+	if b.Validate != nil {
+		return b.Validate(v)
+	}
+	return nil
+}
+
+// bindBody decodes the body of r into v by Content-Type.
+// The errors of fields are returned as fields, and other errors are returned as err.
+func (b *Binder) bindBody(r *http.Request, v interface{}) (fields []*FieldError, err error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return nil, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Type: %v", err)
+	}
+	maxSize := b.MaxBodySize
+	if maxSize == 0 {
+		maxSize = DefaultMaxBodySize
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		body := io.LimitReader(r.Body, maxSize+1)
+		lr := &countingReader{r: body}
+		if err := json.NewDecoder(lr).Decode(v); err != nil {
+			if lr.n > maxSize {
+				return nil, fmt.Errorf("request body too large")
+			}
+			if err == io.EOF {
+				return nil, nil
+			}
+			if te, ok := err.(*json.UnmarshalTypeError); ok {
+				return []*FieldError{{Source: SourceBody, Name: te.Field, Err: fmt.Errorf("must be %v", te.Type)}}, nil
+			}
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+	case mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data":
+		r.Body = http.MaxBytesReader(nil, r.Body, maxSize)
+		if mediaType == "multipart/form-data" {
+			err = r.ParseMultipartForm(maxSize)
+		} else {
+			err = r.ParseForm()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid form: %v", err)
+		}
+		return bindFields(reflect.ValueOf(v).Elem(), func(f reflect.StructField) (string, string, []string, bool) {
+			if name, found := f.Tag.Lookup(SourceForm); found {
+				return SourceForm, name, r.PostForm[name], false
+			}
+			return "", "", nil, false
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported Content-Type `%v'", mediaType)
+	}
+	return nil, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// bindFields sets the values that returned by lookup to the fields of rv.
+// lookup returns the source, the name and the values for the field, and whether the value is required.
+func bindFields(rv reflect.Value, lookup func(f reflect.StructField) (source, name string, values []string, required bool)) []*FieldError {
+	var errs []*FieldError
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			errs = append(errs, bindFields(rv.Field(i), lookup)...)
+			continue
+		}
+		source, name, values, required := lookup(f)
+		if len(values) == 0 {
+			if required {
+				errs = append(errs, &FieldError{Source: source, Name: name, Err: denco.ErrParamNotFound})
+			}
+			continue
+		}
+		if err := setValues(rv.Field(i), values); err != nil {
+			errs = append(errs, &FieldError{Source: source, Name: name, Value: values[0], Err: err})
+		}
+	}
+	return errs
+}
+
+// setValues sets values to fv. All values are used if fv is a slice, otherwise the first value is used.
+func setValues(fv reflect.Value, values []string) error {
+	if fv.Kind() == reflect.Slice && !reflect.PtrTo(fv.Type()).Implements(textUnmarshalerType) {
+		s := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, v := range values {
+			if err := bind.SetValue(s.Index(i), v); err != nil {
+				return err
+			}
+		}
+		fv.Set(s)
+		return nil
+	}
+	return bind.SetValue(fv, values[0])
+}
@@ -0,0 +1,137 @@
+package binding_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/binding"
+)
+
+type createComment struct {
+	PostID int64    `path:"id"`
+	Page   int      `query:"page"`
+	Tags   []string `query:"tag"`
+	Token  string   `header:"X-Token"`
+	Body   string   `json:"body" form:"body"`
+	Draft  bool     `json:"draft" form:"draft"`
+}
+
+func (c *createComment) Validate() error {
+	if c.Body == "" {
+		return binding.NewFieldError(binding.SourceBody, "body", "must not be empty")
+	}
+	return nil
+}
+
+func TestBind(t *testing.T) {
+	params := denco.Params{{Name: "id", Value: "42"}}
+	for _, v := range []struct {
+		contentType, body string
+	}{
+		{"application/json", `{"body": "hello", "draft": true}`},
+		{"application/x-www-form-urlencoded", `body=hello&draft=true`},
+	} {
+		req := httptest.NewRequest("POST", "/posts/42/comments?page=2&tag=a&tag=b", strings.NewReader(v.body))
+		req.Header.Set("Content-Type", v.contentType)
+		req.Header.Set("X-Token", "secret")
+		var actual createComment
+		if err := binding.Bind(req, params, &actual); err != nil {
+			t.Errorf("Bind with %v => %v", v.contentType, err)
+			continue
+		}
+		expected := createComment{PostID: 42, Page: 2, Tags: []string{"a", "b"}, Token: "secret", Body: "hello", Draft: true}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Bind with %v => %#v, want %#v", v.contentType, actual, expected)
+		}
+	}
+}
+
+func TestBind_required(t *testing.T) {
+	var actual struct {
+		ID   int64  `path:"id,required,omitempty"`
+		Name string `path:"name"`
+	}
+	req := httptest.NewRequest("GET", "/posts", nil)
+	err := binding.Bind(req, nil, &actual)
+	e, ok := err.(*binding.Error)
+	if !ok || len(e.Fields) != 1 || e.Fields[0].Source != binding.SourcePath || e.Fields[0].Name != "id" {
+		t.Errorf("Bind without required path parameter => %#v, want the error of path `id'", err)
+	}
+}
+
+func TestBind_withInvalidRequest(t *testing.T) {
+	for _, v := range []struct {
+		params           denco.Params
+		url, ctype, body string
+		fields           []string
+		hasErr           bool
+	}{
+		{denco.Params{{Name: "id", Value: "x"}}, "/?page=y", "application/json", `{"body": "a", "draft": "z"}`, []string{"body:draft", "path:id", "query:page"}, false},
+		{nil, "/", "application/json", `{"body": `, nil, true},
+		{nil, "/", "text/plain", `body`, nil, true},
+		{nil, "/", "application/json", `{"body": ""}`, []string{"body:body"}, false},
+	} {
+		req := httptest.NewRequest("POST", v.url, strings.NewReader(v.body))
+		req.Header.Set("Content-Type", v.ctype)
+		var c createComment
+		err := binding.Bind(req, v.params, &c)
+		e, ok := err.(*binding.Error)
+		if !ok {
+			t.Errorf("Bind(%q) => %#v, want *binding.Error", v.body, err)
+			continue
+		}
+		var fields []string
+		for _, f := range e.Fields {
+			fields = append(fields, f.Source+":"+f.Name)
+		}
+		if !reflect.DeepEqual(fields, v.fields) || (e.Err != nil) != v.hasErr {
+			t.Errorf("Bind(%q) => %v %v, want %v %v", v.body, fields, e.Err, v.fields, v.hasErr)
+		}
+	}
+}
+
+func TestBinder_Validate(t *testing.T) {
+	b := &binding.Binder{Validate: func(v interface{}) error {
+		return errors.New("invalid")
+	}}
+	req := httptest.NewRequest("GET", "/", nil)
+	var v struct{}
+	if err := b.Bind(req, nil, &v); err == nil || err.Error() != "binding: invalid" {
+		t.Errorf("Binder.Bind => %v, want %v", err, "binding: invalid")
+	}
+}
+
+func TestBinder_MaxBodySize(t *testing.T) {
+	b := &binding.Binder{MaxBodySize: 8}
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"body": "too large"}`))
+	req.Header.Set("Content-Type", "application/json")
+	var c createComment
+	if err := b.Bind(req, nil, &c); err == nil {
+		t.Errorf("Binder.Bind with too large body => nil, want error")
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	for _, v := range []struct {
+		err      error
+		status   int
+		expected string
+	}{
+		{binding.NewFieldError(binding.SourceQuery, "page", "must be positive"), 400, `{"error":"Bad Request","fields":[{"in":"query","name":"page","message":"must be positive"}]}` + "\n"},
+		{&binding.Error{Err: errors.New("invalid JSON")}, 400, `{"error":"Bad Request","message":"invalid JSON"}` + "\n"},
+		{errors.New("secret"), 500, `{"error":"Internal Server Error"}` + "\n"},
+	} {
+		w := httptest.NewRecorder()
+		binding.WriteError(w, v.err)
+		if w.Code != v.status || w.Body.String() != v.expected {
+			t.Errorf("WriteError(%v) => %v %v, want %v %v", v.err, w.Code, w.Body.String(), v.status, v.expected)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+			t.Errorf("WriteError(%v) => Content-Type %v", v.err, ct)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package binding
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// FieldError represents an error of the value for a field.
+type FieldError struct {
+	// Source is the source of the value, such as SourceQuery.
+	Source string
+
+	// Name is a name of the value in the source.
+	Name string
+
+	// Value is the invalid value.
+	Value string
+
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s `%s': %v", e.Source, e.Name, e.Err)
+}
+
+// Error represents an error of binding.
+type Error struct {
+	// Fields is a list of errors of fields.
+	Fields []*FieldError
+
+	// Err is an error that isn't related to a specific field, such as a malformed body
+	// or an error returned by validation.
+	Err error
+}
+
+// NewFieldError returns a new *Error for the field.
+// It is intended to be returned from Validator.
+func NewFieldError(source, name, format string, args ...interface{}) *Error {
+	return &Error{Fields: []*FieldError{{Source: source, Name: name, Err: fmt.Errorf(format, args...)}}}
+}
+
+func (e *Error) Error() string {
+	var msgs []string
+	if e.Err != nil {
+		msgs = append(msgs, e.Err.Error())
+	}
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Error())
+	}
+	return "binding: " + strings.Join(msgs, "; ")
+}
+
+// StatusCode returns http.StatusBadRequest.
+func (e *Error) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// errorResponse is the body of the response that written by WriteError.
+type errorResponse struct {
+	Error   string        `json:"error"`
+	Message string        `json:"message,omitempty"`
+	Fields  []fieldDetail `json:"fields,omitempty"`
+}
+
+type fieldDetail struct {
+	In      string `json:"in"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// WriteError writes err as a JSON response.
+// If err is *Error, the response is 400 Bad Request with the details of the error:
+//
+//	{"error": "Bad Request", "fields": [{"in": "query", "name": "page", "message": "must be an integer in range of int"}]}
+//
+// Otherwise, the response is 500 Internal Server Error without details.
+func WriteError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	res := errorResponse{}
+	if e, ok := err.(*Error); ok {
+		status = e.StatusCode()
+		if e.Err != nil {
+			res.Message = e.Err.Error()
+		}
+		for _, f := range e.Fields {
+			res.Fields = append(res.Fields, fieldDetail{In: f.Source, Name: f.Name, Message: f.Err.Error()})
+		}
+	}
+	res.Error = http.StatusText(status)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
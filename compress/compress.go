@@ -0,0 +1,153 @@
+// Package compress provides a middleware that compresses responses of http.Handlers built by denco.Mux.
+//
+//	c := compress.New()
+//	c.SetEncoder("br", newBrotliWriter) // optional
+//	handler, _ := mux.Build(handlers)
+//	http.ListenAndServe(":8080", c.Middleware(handler))
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/naoina/denco/internal/httputil"
+)
+
+// DefaultMinSize is the default value of Compressor.MinSize.
+const DefaultMinSize = 1024
+
+// EncoderFunc returns a writer that writes the data compressed to w.
+// The returned writer may implement Flush() error to support http.Flusher.
+type EncoderFunc func(w io.Writer) (io.WriteCloser, error)
+
+// DefaultSkipTypes is the default value of Compressor.SkipTypes.
+var DefaultSkipTypes = []string{
+	"image/*",
+	"video/*",
+	"audio/*",
+	"font/woff",
+	"font/woff2",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/zstd",
+	"application/x-bzip2",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/pdf",
+	"application/octet-stream",
+}
+
+// Compressor compresses responses.
+type Compressor struct {
+	// MinSize is the minimum size of the body to compress.
+	// Bodies that are smaller than it are sent as is unless the handler flushes them.
+	MinSize int
+
+	// SkipTypes is the list of media types that are not compressed such as "image/*".
+	// "image/svg+xml" is compressed even if "image/*" is listed, since it is text.
+	SkipTypes []string
+
+	encoders []encoder
+}
+
+type encoder struct {
+	coding string
+	fn     EncoderFunc
+}
+
+// New returns a new Compressor that supports gzip and deflate.
+func New() *Compressor {
+	c := &Compressor{
+		MinSize:   DefaultMinSize,
+		SkipTypes: DefaultSkipTypes,
+	}
+	c.SetEncoder("deflate", func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, flate.DefaultCompression)
+	})
+	c.SetEncoder("gzip", func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	})
+	return c
+}
+
+// SetEncoder sets fn as the encoder for the content coding such as "br".
+// If fn is nil, the content coding is removed.
+// When the client accepts multiple content codings with the same quality, the encoder
+// that is set later is preferred, so that gzip is preferred to deflate by default.
+func (c *Compressor) SetEncoder(coding string, fn EncoderFunc) {
+	coding = strings.ToLower(coding)
+	for i, e := range c.encoders {
+		if e.coding == coding {
+			c.encoders = append(c.encoders[:i], c.encoders[i+1:]...)
+			break
+		}
+	}
+	if fn != nil {
+		c.encoders = append([]encoder{{coding: coding, fn: fn}}, c.encoders...)
+	}
+}
+
+// Middleware returns a http.Handler that compresses responses of next.
+func (c *Compressor) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		enc := c.negotiate(r)
+		if enc == nil || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &responseWriter{ResponseWriter: w, c: c, enc: enc}
+		next.ServeHTTP(cw, r)
+		cw.close()
+	})
+}
+
+// negotiate returns the encoder that has the highest quality in Accept-Encoding header of r.
+// negotiate returns nil if r accepts no encoder.
+func (c *Compressor) negotiate(r *http.Request) *encoder {
+	quality := make(map[string]float64)
+	for _, v := range r.Header["Accept-Encoding"] {
+		for _, item := range strings.Split(v, ",") {
+			if coding, q := httputil.ParseQuality(item); coding != "" {
+				quality[coding] = q
+			}
+		}
+	}
+	var best *encoder
+	var bestQ float64
+	for i, e := range c.encoders {
+		q, ok := quality[e.coding]
+		if !ok {
+			q = quality["*"]
+		}
+		if q > bestQ {
+			best, bestQ = &c.encoders[i], q
+		}
+	}
+	return best
+}
+
+// compressible reports whether the response that has header h should be compressed.
+func (c *Compressor) compressible(h http.Header) bool {
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	if mediaType == "image/svg+xml" {
+		return true
+	}
+	for _, t := range c.SkipTypes {
+		if t == mediaType || (strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, t[:len(t)-1])) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,254 @@
+package compress_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/compress"
+)
+
+var largeText = strings.Repeat("denco is a fast URL router. ", 100)
+
+func newTestHandler(t *testing.T) http.Handler {
+	mux := denco.NewMux()
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/text", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			w.Header().Set("ETag", `"v1"`)
+			io.WriteString(w, largeText)
+		}),
+		mux.GET("/small", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			io.WriteString(w, "small")
+		}),
+		mux.GET("/image", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			w.Header().Set("Content-Type", "image/png")
+			io.WriteString(w, largeText)
+		}),
+		mux.GET("/svg", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			w.Header().Set("Content-Type", "image/svg+xml")
+			io.WriteString(w, largeText)
+		}),
+		mux.GET("/encoded", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			w.Header().Set("Content-Encoding", "br")
+			io.WriteString(w, largeText)
+		}),
+		mux.GET("/stream", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			io.WriteString(w, "data: 1\n\n")
+			w.(http.Flusher).Flush()
+			io.WriteString(w, "data: 2\n\n")
+		}),
+		mux.GET("/notmodified", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			w.WriteHeader(http.StatusNotModified)
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return handler
+}
+
+func decode(t *testing.T, coding string, body []byte) string {
+	var r io.Reader
+	switch coding {
+	case "gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gr
+	case "deflate":
+		r = flate.NewReader(bytes.NewReader(body))
+	default:
+		return string(body)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestCompressor(t *testing.T) {
+	h := compress.New().Middleware(newTestHandler(t))
+	for _, v := range []struct {
+		method, path   string
+		acceptEncoding string
+		encoding       string
+		contentType    string
+		body           string
+	}{
+		{"GET", "/text", "gzip, deflate", "gzip", "text/plain; charset=utf-8", largeText},
+		{"GET", "/text", "deflate", "deflate", "text/plain; charset=utf-8", largeText},
+		{"GET", "/text", "gzip;q=0.5, deflate", "deflate", "text/plain; charset=utf-8", largeText},
+		{"GET", "/text", "*", "gzip", "text/plain; charset=utf-8", largeText},
+		{"GET", "/text", "gzip;q=0", "", "text/plain; charset=utf-8", largeText},
+		{"GET", "/text", "br", "", "text/plain; charset=utf-8", largeText},
+		{"GET", "/text", "", "", "text/plain; charset=utf-8", largeText},
+		{"GET", "/small", "gzip", "", "text/plain; charset=utf-8", "small"},
+		{"GET", "/image", "gzip", "", "image/png", largeText},
+		{"GET", "/svg", "gzip", "gzip", "image/svg+xml", largeText},
+		{"GET", "/encoded", "gzip", "br", "text/plain; charset=utf-8", largeText},
+		{"GET", "/stream", "gzip", "gzip", "text/event-stream", "data: 1\n\ndata: 2\n\n"},
+		{"GET", "/notmodified", "gzip", "", "", ""},
+	} {
+		req := httptest.NewRequest(v.method, v.path, nil)
+		if v.acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", v.acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		encoding := w.Header().Get("Content-Encoding")
+		if encoding != v.encoding {
+			t.Errorf("%s %s with %q => Content-Encoding %q, want %q", v.method, v.path, v.acceptEncoding, encoding, v.encoding)
+		}
+		if actual := w.Header().Get("Content-Type"); actual != v.contentType {
+			t.Errorf("%s %s with %q => Content-Type %q, want %q", v.method, v.path, v.acceptEncoding, actual, v.contentType)
+		}
+		if actual := w.Header().Get("Vary"); actual != "Accept-Encoding" {
+			t.Errorf("%s %s with %q => Vary %q, want %q", v.method, v.path, v.acceptEncoding, actual, "Accept-Encoding")
+		}
+		if actual := decode(t, encoding, w.Body.Bytes()); actual != v.body {
+			t.Errorf("%s %s with %q => body %q, want %q", v.method, v.path, v.acceptEncoding, actual, v.body)
+		}
+	}
+}
+
+func TestCompressor_ETag(t *testing.T) {
+	h := compress.New().Middleware(newTestHandler(t))
+	for _, v := range []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"gzip", `W/"v1"`},
+		{"", `"v1"`},
+	} {
+		req := httptest.NewRequest("GET", "/text", nil)
+		req.Header.Set("Accept-Encoding", v.acceptEncoding)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if actual := w.Header().Get("ETag"); actual != v.expected {
+			t.Errorf("ETag with %q => %q, want %q", v.acceptEncoding, actual, v.expected)
+		}
+	}
+}
+
+type upperWriter struct {
+	w io.Writer
+}
+
+func (w upperWriter) Write(b []byte) (int, error) {
+	return w.w.Write(bytes.ToUpper(b))
+}
+
+func (w upperWriter) Close() error {
+	return nil
+}
+
+func TestCompressor_SetEncoder(t *testing.T) {
+	c := compress.New()
+	c.MinSize = 0
+	c.SetEncoder("upper", func(w io.Writer) (io.WriteCloser, error) {
+		return upperWriter{w}, nil
+	})
+	c.SetEncoder("deflate", nil)
+	h := c.Middleware(newTestHandler(t))
+	for _, v := range []struct {
+		acceptEncoding string
+		encoding       string
+		body           string
+	}{
+		{"gzip, upper", "upper", "SMALL"},
+		{"gzip, upper;q=0.5", "gzip", "small"},
+		{"deflate", "", "small"},
+	} {
+		req := httptest.NewRequest("GET", "/small", nil)
+		req.Header.Set("Accept-Encoding", v.acceptEncoding)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		encoding := w.Header().Get("Content-Encoding")
+		if encoding != v.encoding {
+			t.Errorf("Content-Encoding with %q => %q, want %q", v.acceptEncoding, encoding, v.encoding)
+		}
+		if actual := decode(t, encoding, w.Body.Bytes()); actual != v.body {
+			t.Errorf("body with %q => %q, want %q", v.acceptEncoding, actual, v.body)
+		}
+	}
+}
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (w *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
+
+func TestCompressor_Hijack(t *testing.T) {
+	var errs []error
+	h := compress.New().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack()
+		errs = append(errs, err)
+		if r.URL.Path == "/write" {
+			io.WriteString(w, largeText)
+			_, _, err := w.(http.Hijacker).Hijack()
+			errs = append(errs, err)
+		}
+	}))
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	h.ServeHTTP(w, req)
+	if !w.hijacked || errs[0] != nil || w.Body.Len() != 0 {
+		t.Errorf("Hijack => hijacked %v, error %v, body %q, want true, nil, empty", w.hijacked, errs[0], w.Body.String())
+	}
+
+	errs = nil
+	req = httptest.NewRequest("GET", "/write", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if len(errs) != 2 || errs[0] == nil || errs[1] == nil {
+		t.Errorf("Hijack of unsupported writer and after write => %v, want errors", errs)
+	}
+}
+
+func TestCompressor_partialContent(t *testing.T) {
+	mux := denco.NewMux()
+	handler, err := mux.Build(mux.ServeFiles("/static", http.FS(fstest.MapFS{
+		"large.txt": {Data: []byte(largeText)},
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := compress.New().Middleware(handler)
+	req := httptest.NewRequest("GET", "/static/large.txt", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Range", "bytes=0-1999")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("GET with Range => %v, want %v", w.Code, http.StatusPartialContent)
+	}
+	if actual := w.Header().Get("Content-Encoding"); actual != "" {
+		t.Errorf("GET with Range => Content-Encoding %q, want %q", actual, "")
+	}
+	if actual, expected := w.Header().Get("Content-Range"), fmt.Sprintf("bytes 0-1999/%d", len(largeText)); actual != expected {
+		t.Errorf("GET with Range => Content-Range %q, want %q", actual, expected)
+	}
+	if actual, expected := w.Body.String(), largeText[:2000]; actual != expected {
+		t.Errorf("GET with Range => body %q, want %q", actual, expected)
+	}
+}
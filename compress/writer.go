@@ -0,0 +1,178 @@
+package compress
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// responseWriter buffers the beginning of the body to decide whether it is compressed.
+type responseWriter struct {
+	http.ResponseWriter
+
+	c   *Compressor
+	enc *encoder
+
+	// status is the status code that is written by the handler.
+	status int
+
+	// buf is the beginning of the body until it is decided.
+	buf []byte
+
+	decided  bool
+	hijacked bool
+
+	// w is the writer of the encoder. It is nil if the body isn't compressed.
+	w io.WriteCloser
+}
+
+// WriteHeader implements http.ResponseWriter.WriteHeader.
+func (w *responseWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status != 0 {
+		return
+	}
+	if code < 200 {
+		// Informational responses such as "103 Early Hints" are sent as is.
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+	if !bodyAllowed(code) || w.Header().Get("Content-Encoding") != "" {
+		w.decide(false)
+		return
+	}
+	if code == http.StatusPartialContent || w.Header().Get("Content-Range") != "" {
+		// Content-Range refers to the offsets of the uncompressed representation.
+		w.decide(false)
+		return
+	}
+	if n, err := strconv.Atoi(w.Header().Get("Content-Length")); err == nil && n < w.c.MinSize {
+		w.decide(false)
+	}
+}
+
+// Write implements http.ResponseWriter.Write.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.decided {
+		w.buf = append(w.buf, b...)
+		if len(w.buf) < w.c.MinSize {
+			return len(b), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if w.w != nil {
+		return w.w.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// decide writes the header and the buffered body.
+// The body is compressed if compress is true and the content type is compressible.
+func (w *responseWriter) decide(compress bool) error {
+	w.decided = true
+	h := w.Header()
+	if _, ok := h["Content-Type"]; !ok && bodyAllowed(w.status) {
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+	if compress && w.c.compressible(h) {
+		if enc, err := w.enc.fn(w.ResponseWriter); err == nil {
+			w.w = enc
+			h.Set("Content-Encoding", w.enc.coding)
+			h.Del("Content-Length")
+			if etag := h.Get("Etag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+				// The compressed representation isn't byte-for-byte identical.
+				h.Set("Etag", "W/"+etag)
+			}
+		}
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.w != nil {
+		_, err = w.w.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close writes the rest of the response after the handler returned.
+func (w *responseWriter) close() error {
+	if w.hijacked {
+		return nil
+	}
+	if !w.decided {
+		if err := w.decide(false); err != nil {
+			return err
+		}
+	}
+	if w.w != nil {
+		return w.w.Close()
+	}
+	return nil
+}
+
+// Flush implements http.Flusher.
+// The body is compressed regardless of MinSize if it is flushed before it is decided.
+func (w *responseWriter) Flush() {
+	if w.hijacked {
+		return
+	}
+	if !w.decided {
+		w.decide(true)
+	}
+	if f, ok := w.w.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker.
+// Hijack fails if the response has been written.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.decided {
+		return nil, nil, fmt.Errorf("compress: Hijack after the response has been written")
+	}
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("compress: %T does not implement http.Hijacker", w.ResponseWriter)
+	}
+	conn, rw, err := hj.Hijack()
+	if err == nil {
+		w.hijacked = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// bodyAllowed reports whether a response with the status code can have a body.
+func bodyAllowed(code int) bool {
+	return !(code >= 100 && code < 200) && code != http.StatusNoContent && code != http.StatusNotModified
+}
@@ -0,0 +1,176 @@
+// Package cors provides a Cross-Origin Resource Sharing middleware for http.Handlers built by denco.Mux.
+//
+// Access-Control-Allow-Methods of preflight responses is derived from the routes that
+// match the requested path, so it is always consistent with the routing table.
+//
+//	handler, _ := mux.Build(handlers)
+//	c := cors.New("https://example.com", "https://*.example.com")
+//	c.AllowCredentials = true
+//	http.ListenAndServe(":8080", c.Middleware(handler))
+package cors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/naoina/denco"
+)
+
+// CORS is the configuration of a CORS middleware.
+type CORS struct {
+	// AllowOrigins is the list of allowed origins such as "https://example.com".
+	// An origin may have a wildcard as the leftmost label of the host such as "https://*.example.com".
+	// "*" allows any origin.
+	AllowOrigins []string
+
+	// AllowOriginFunc reports whether origin is allowed in addition to AllowOrigins.
+	AllowOriginFunc func(origin string) bool
+
+	// AllowHeaders is the list of request headers allowed in preflight responses.
+	// If empty, the headers of Access-Control-Request-Headers are allowed.
+	AllowHeaders []string
+
+	// ExposeHeaders is the list of response headers that are exposed to the client.
+	ExposeHeaders []string
+
+	// AllowCredentials allows requests with credentials such as cookies.
+	// It cannot be used with "*" of AllowOrigins, because it would allow any origin
+	// to make requests with the credentials of the user. Middleware panics in that case.
+	AllowCredentials bool
+
+	// MaxAge is how long the result of a preflight request can be cached.
+	// If zero, Access-Control-Max-Age is not sent.
+	MaxAge time.Duration
+
+	// AllowMethods is the list of methods allowed in preflight responses for paths that
+	// are handled by handlers that match any method such as Mux.Mount, or if the handler
+	// isn't built by Mux.Build. If empty, the method of Access-Control-Request-Method is allowed.
+	AllowMethods []string
+}
+
+// New returns a new CORS that allows origins.
+func New(origins ...string) *CORS {
+	return &CORS{AllowOrigins: origins}
+}
+
+// Middleware returns a http.Handler that handles CORS requests to next.
+// Preflight requests are answered by the middleware if the path matches any route of next,
+// otherwise they are passed to next. Requests from disallowed origins are passed to next
+// without CORS headers.
+// Middleware panics if AllowOrigins has "*" and AllowCredentials is true.
+func (c *CORS) Middleware(next http.Handler) http.Handler {
+	if c.AllowCredentials {
+		for _, o := range c.AllowOrigins {
+			if o == "*" {
+				panic("cors: AllowCredentials cannot be used with AllowOrigins \"*\"")
+			}
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		if !c.allowed(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if methods := c.allowMethods(next, r); len(methods) > 0 {
+				c.preflight(w, r, origin, methods)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		c.setOrigin(h, origin)
+		if len(c.ExposeHeaders) > 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(c.ExposeHeaders, ", "))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *CORS) preflight(w http.ResponseWriter, r *http.Request, origin string, methods []string) {
+	h := w.Header()
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+	c.setOrigin(h, origin)
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if len(c.AllowHeaders) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(c.AllowHeaders, ", "))
+	} else if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+		h.Set("Access-Control-Allow-Headers", headers)
+	}
+	if c.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.FormatInt(int64(c.MaxAge/time.Second), 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *CORS) setOrigin(h http.Header, origin string) {
+	if c.AllowCredentials {
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Credentials", "true")
+		return
+	}
+	for _, o := range c.AllowOrigins {
+		if o == "*" {
+			h.Set("Access-Control-Allow-Origin", "*")
+			return
+		}
+	}
+	h.Set("Access-Control-Allow-Origin", origin)
+}
+
+// allowMethods returns the methods allowed for the path of r.
+// allowMethods returns nil if no route matches the path.
+func (c *CORS) allowMethods(next http.Handler, r *http.Request) []string {
+	methods, ok := denco.AllowedMethods(next, r)
+	if ok {
+		for _, m := range methods {
+			if m == "*" {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return methods
+		}
+	}
+	if len(c.AllowMethods) > 0 {
+		return c.AllowMethods
+	}
+	return []string{r.Header.Get("Access-Control-Request-Method")}
+}
+
+func (c *CORS) allowed(origin string) bool {
+	for _, o := range c.AllowOrigins {
+		if matchOrigin(o, origin) {
+			return true
+		}
+	}
+	return c.AllowOriginFunc != nil && c.AllowOriginFunc(origin)
+}
+
+// matchOrigin reports whether origin matches pattern.
+func matchOrigin(pattern, origin string) bool {
+	if pattern == "*" || strings.EqualFold(pattern, origin) {
+		return true
+	}
+	i := strings.Index(pattern, "://*.")
+	if i < 0 {
+		return false
+	}
+	scheme, suffix := pattern[:i+len("://")], pattern[i+len("://*"):]
+	if len(origin) <= len(scheme)+len(suffix) || !strings.EqualFold(origin[:len(scheme)], scheme) {
+		return false
+	}
+	host := origin[len(scheme):]
+	return strings.HasSuffix(strings.ToLower(host), strings.ToLower(suffix)) && strings.IndexAny(host[:len(host)-len(suffix)], "/:") < 0
+}
@@ -0,0 +1,151 @@
+package cors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/cors"
+)
+
+func newTestHandler(t *testing.T) http.Handler {
+	mux := denco.NewMux()
+	handlerFunc := func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		w.Header().Set("X-Request-Id", "1")
+	}
+	handlers := []denco.Handler{
+		mux.GET("/user/:id", handlerFunc),
+		mux.PUT("/user/:id", handlerFunc),
+		mux.Handler("DELETE", "/user/:id", handlerFunc),
+	}
+	handlers = append(handlers, mux.Mount("/legacy", http.NotFoundHandler())...)
+	handler, err := mux.Build(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return handler
+}
+
+func TestCORS_preflight(t *testing.T) {
+	c := cors.New("https://example.com", "https://*.example.org")
+	c.AllowHeaders = []string{"Content-Type", "Authorization"}
+	c.MaxAge = 10 * time.Minute
+	h := c.Middleware(newTestHandler(t))
+	for _, v := range []struct {
+		origin, path string
+		status       int
+		headers      http.Header
+	}{
+		{"https://example.com", "/user/1", http.StatusNoContent, http.Header{
+			"Vary":                         {"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			"Access-Control-Allow-Origin":  {"https://example.com"},
+			"Access-Control-Allow-Methods": {"DELETE, GET, PUT"},
+			"Access-Control-Allow-Headers": {"Content-Type, Authorization"},
+			"Access-Control-Max-Age":       {"600"},
+		}},
+		{"https://api.example.org", "/legacy/user", http.StatusNoContent, http.Header{
+			"Vary":                         {"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			"Access-Control-Allow-Origin":  {"https://api.example.org"},
+			"Access-Control-Allow-Methods": {"PATCH"},
+			"Access-Control-Allow-Headers": {"Content-Type, Authorization"},
+			"Access-Control-Max-Age":       {"600"},
+		}},
+		{"https://example.com", "/missing", http.StatusNotFound, http.Header{
+			"Vary":                   {"Origin"},
+			"Content-Type":           {"text/plain; charset=utf-8"},
+			"X-Content-Type-Options": {"nosniff"},
+		}},
+		{"https://evil.example.com", "/user/1", http.StatusNotFound, http.Header{
+			"Vary":                   {"Origin"},
+			"Content-Type":           {"text/plain; charset=utf-8"},
+			"X-Content-Type-Options": {"nosniff"},
+		}},
+		{"https://example.org", "/user/1", http.StatusNotFound, http.Header{
+			"Vary":                   {"Origin"},
+			"Content-Type":           {"text/plain; charset=utf-8"},
+			"X-Content-Type-Options": {"nosniff"},
+		}},
+	} {
+		req := httptest.NewRequest("OPTIONS", v.path, nil)
+		req.Header.Set("Origin", v.origin)
+		req.Header.Set("Access-Control-Request-Method", "PATCH")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != v.status {
+			t.Errorf("preflight %q from %q => status %v, want %v", v.path, v.origin, w.Code, v.status)
+		}
+		if actual := w.Header(); !reflect.DeepEqual(actual, v.headers) {
+			t.Errorf("preflight %q from %q => headers %#v, want %#v", v.path, v.origin, actual, v.headers)
+		}
+	}
+}
+
+func TestCORS_actual(t *testing.T) {
+	handler := newTestHandler(t)
+	for _, v := range []struct {
+		allowOrigin string
+		credentials bool
+		origin      string
+		headers     http.Header
+	}{
+		{"*", false, "https://example.com", http.Header{
+			"Vary":                          {"Origin"},
+			"Access-Control-Allow-Origin":   {"*"},
+			"Access-Control-Expose-Headers": {"X-Request-Id"},
+			"X-Request-Id":                  {"1"},
+		}},
+		{"https://example.com", true, "https://example.com", http.Header{
+			"Vary":                             {"Origin"},
+			"Access-Control-Allow-Origin":      {"https://example.com"},
+			"Access-Control-Allow-Credentials": {"true"},
+			"Access-Control-Expose-Headers":    {"X-Request-Id"},
+			"X-Request-Id":                     {"1"},
+		}},
+		{"*", false, "", http.Header{
+			"X-Request-Id": {"1"},
+		}},
+	} {
+		c := cors.New(v.allowOrigin)
+		c.ExposeHeaders = []string{"X-Request-Id"}
+		c.AllowCredentials = v.credentials
+		h := c.Middleware(handler)
+		req := httptest.NewRequest("GET", "/user/1", nil)
+		if v.origin != "" {
+			req.Header.Set("Origin", v.origin)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if actual := w.Header(); !reflect.DeepEqual(actual, v.headers) {
+			t.Errorf("GET from %q with credentials %v => headers %#v, want %#v", v.origin, v.credentials, actual, v.headers)
+		}
+	}
+}
+
+func TestCORS_AllowOriginFunc(t *testing.T) {
+	c := cors.New()
+	c.AllowOriginFunc = func(origin string) bool {
+		return origin == "null"
+	}
+	h := c.Middleware(newTestHandler(t))
+	req := httptest.NewRequest("GET", "/user/1", nil)
+	req.Header.Set("Origin", "null")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if actual, expected := w.Header().Get("Access-Control-Allow-Origin"), "null"; actual != expected {
+		t.Errorf("Access-Control-Allow-Origin => %q, want %q", actual, expected)
+	}
+}
+
+func TestCORS_Middleware_wildcardWithCredentials(t *testing.T) {
+	c := cors.New("https://example.com", "*")
+	c.AllowCredentials = true
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Middleware with AllowOrigins %q and AllowCredentials => no panic, want panic", c.AllowOrigins)
+		}
+	}()
+	c.Middleware(newTestHandler(t))
+}
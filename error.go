@@ -0,0 +1,98 @@
+package denco
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HandlerFuncE is the type of handler function that returns an error.
+// Use Mux.E to convert it to HandlerFunc.
+type HandlerFuncE func(w http.ResponseWriter, r *http.Request, params Params) error
+
+// E returns a HandlerFunc that calls handler and passes the returned error to
+// the ErrorHandler of m, e.g.
+//
+//	mux.GET("/user/:id", mux.E(GetUser))
+func (m *Mux) E(handler HandlerFuncE) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params Params) {
+		if err := handler(w, r, params); err != nil {
+			if m.ErrorHandler != nil {
+				m.ErrorHandler(w, r, err)
+			} else {
+				ErrorHandler(w, r, err)
+			}
+		}
+	}
+}
+
+// StatusCoder is the interface that is implemented by errors that have an HTTP status code.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// HTTPError represents an error that has an HTTP status code.
+type HTTPError struct {
+	// Code is an HTTP status code.
+	Code int
+
+	// Message is a message for the client.
+	// If empty, the status text of Code will be used.
+	Message string
+
+	// Err is the underlying error. It will not be sent to the client.
+	Err error
+}
+
+// NewHTTPError returns a new HTTPError.
+func NewHTTPError(code int, message string) *HTTPError {
+	return &HTTPError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *HTTPError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.Code)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%d %s: %v", e.Code, msg, e.Err)
+	}
+	return fmt.Sprintf("%d %s", e.Code, msg)
+}
+
+// StatusCode returns the HTTP status code.
+func (e *HTTPError) StatusCode() int {
+	return e.Code
+}
+
+// Unwrap returns the underlying error.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorHandler replies to the request with the error that returned by a handler converted by Mux.E.
+// If err is or wraps a StatusCoder, its status code is used, otherwise 500 Internal Server Error.
+// The message of HTTPError and the messages of other errors of 4xx status are sent to the client.
+// If you want to use globally your own ErrorHandler, please overwrite this variable.
+// If you want to use your own ErrorHandler for each Mux, use Mux.ErrorHandler instead.
+var ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+	code, msg := http.StatusInternalServerError, ""
+	var sc StatusCoder
+	if errors.As(err, &sc) {
+		code = sc.StatusCode()
+		var he *HTTPError
+		switch {
+		case errors.As(err, &he):
+			msg = he.Message
+		case code < http.StatusInternalServerError:
+			msg = err.Error()
+		}
+	}
+	if msg == "" {
+		msg = http.StatusText(code)
+	}
+	http.Error(w, fmt.Sprintf("%d %s", code, msg), code)
+}
@@ -0,0 +1,135 @@
+package denco_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/naoina/denco"
+)
+
+func TestMux_E(t *testing.T) {
+	handlerFunc := func(w http.ResponseWriter, r *http.Request, params denco.Params) error {
+		switch params.Get("kind") {
+		case "ok":
+			fmt.Fprint(w, "ok")
+			return nil
+		case "http":
+			return denco.NewHTTPError(http.StatusConflict, "already exists")
+		case "wrapped":
+			return fmt.Errorf("wrapped: %w", &denco.HTTPError{Code: http.StatusForbidden, Err: errors.New("secret")})
+		case "param":
+			_, err := params.Int("kind")
+			return err
+		case "internal":
+			return errors.New("secret")
+		}
+		return denco.NewHTTPError(http.StatusServiceUnavailable, "")
+	}
+	mux := denco.NewMux()
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/:kind", mux.E(handlerFunc)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path     string
+		status   int
+		expected string
+	}{
+		{"/ok", 200, "ok"},
+		{"/http", 409, "409 already exists\n"},
+		{"/wrapped", 403, "403 Forbidden\n"},
+		{"/param", 400, "400 invalid path parameter `kind' \"param\": invalid syntax\n"},
+		{"/internal", 500, "500 Internal Server Error\n"},
+		{"/other", 503, "503 Service Unavailable\n"},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", v.path, nil))
+		if actual := w.Body.String(); w.Code != v.status || actual != v.expected {
+			t.Errorf(`GET "%s" => %#v %#v, want %#v %#v`, v.path, w.Code, actual, v.status, v.expected)
+		}
+	}
+}
+
+func TestMux_ErrorHandler(t *testing.T) {
+	mux := denco.NewMux()
+	mux.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(http.StatusTeapot)
+		fmt.Fprintf(w, "error: %v", err)
+	}
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/", mux.E(func(w http.ResponseWriter, r *http.Request, params denco.Params) error {
+			return errors.New("failed")
+		})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if expected := "error: failed"; w.Code != http.StatusTeapot || w.Body.String() != expected {
+		t.Errorf(`GET "/" => %#v %#v, want %#v %#v`, w.Code, w.Body.String(), http.StatusTeapot, expected)
+	}
+}
+
+func TestMux_PanicHandler(t *testing.T) {
+	mux := denco.NewMux()
+	mux.PanicHandler = func(w http.ResponseWriter, r *http.Request, v interface{}) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "recovered: %v", v)
+	}
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/panic", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			panic("boom")
+		}),
+		mux.GET("/abort", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			panic(http.ErrAbortHandler)
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+	if expected := "recovered: boom"; w.Code != http.StatusInternalServerError || w.Body.String() != expected {
+		t.Errorf(`GET "/panic" => %#v %#v, want %#v %#v`, w.Code, w.Body.String(), http.StatusInternalServerError, expected)
+	}
+	func() {
+		defer func() {
+			if v := recover(); v != http.ErrAbortHandler {
+				t.Errorf(`GET "/abort" => panic %#v, want %#v`, v, http.ErrAbortHandler)
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/abort", nil))
+	}()
+}
+
+func TestMux_PanicHandler_Mount(t *testing.T) {
+	sub := denco.NewMux()
+	sub.PanicHandler = func(w http.ResponseWriter, r *http.Request, v interface{}) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "sub recovered: %v", v)
+	}
+	subHandler, err := sub.Build([]denco.Handler{
+		sub.GET("/boom", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			panic("boom")
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := denco.NewMux()
+	handler, err := mux.Build(mux.Mount("/admin", subHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/admin/boom", nil))
+	if expected := "sub recovered: boom"; w.Code != http.StatusInternalServerError || w.Body.String() != expected {
+		t.Errorf(`GET "/admin/boom" => %#v %#v, want %#v %#v`, w.Code, w.Body.String(), http.StatusInternalServerError, expected)
+	}
+}
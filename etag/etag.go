@@ -0,0 +1,171 @@
+// Package etag provides a middleware that generates ETags of responses and handles
+// conditional requests for handlers of denco.Mux.
+//
+// Responses of GET and HEAD requests are buffered to compute ETags, and the client
+// receives "304 Not Modified" if the response matches If-None-Match or If-Modified-Since,
+// or "412 Precondition Failed" if it doesn't match If-Match or If-Unmodified-Since.
+//
+//	e := etag.New()
+//	handlers := mux.Wrap(e.Wrap,
+//		mux.GET("/user/:id", getUser),
+//		mux.GET("/users", listUsers),
+//	)
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"hash"
+	"net/http"
+	"time"
+
+	"github.com/naoina/denco"
+)
+
+// DefaultMaxBodyBytes is the default value of ETagger.MaxBodyBytes.
+const DefaultMaxBodyBytes = 1 << 20
+
+// ETagger generates ETags and evaluates preconditions of requests.
+type ETagger struct {
+	// Weak makes generated ETags weak validators such as `W/"..."`.
+	Weak bool
+
+	// Hash returns a new hash to compute ETags from response bodies.
+	// If nil, SHA-256 is used.
+	Hash func() hash.Hash
+
+	// MaxBodyBytes is the maximum size of the response body to buffer.
+	// The response that exceeds it is sent without ETag.
+	// If zero, DefaultMaxBodyBytes is used.
+	MaxBodyBytes int
+
+	// Current returns the current ETag of the resource for requests with methods other than GET and HEAD,
+	// such as PUT and DELETE. found is false if the resource doesn't exist.
+	// If nil, preconditions of such requests are not evaluated.
+	Current func(r *http.Request, params denco.Params) (etag string, found bool)
+}
+
+// New returns a new ETagger that generates strong ETags.
+func New() *ETagger {
+	return &ETagger{MaxBodyBytes: DefaultMaxBodyBytes}
+}
+
+// Wrap returns a HandlerFunc that handles conditional requests to fn.
+func (e *ETagger) Wrap(fn denco.HandlerFunc) denco.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			if e.Current != nil {
+				etag, found := e.Current(r, params)
+				if !checkUnsafe(r, etag, found) {
+					w.WriteHeader(http.StatusPreconditionFailed)
+					return
+				}
+			}
+			fn(w, r, params)
+			return
+		}
+		max := e.MaxBodyBytes
+		if max == 0 {
+			max = DefaultMaxBodyBytes
+		}
+		bw := &bufferWriter{ResponseWriter: w, max: max}
+		fn(bw, r, params)
+		if bw.passthrough {
+			return
+		}
+		if bw.status == 0 {
+			bw.status = http.StatusOK
+		}
+		h := w.Header()
+		// A HEAD handler that writes no body cannot have the ETag of the body of GET.
+		if bw.status == http.StatusOK && h.Get("Etag") == "" && (r.Method == http.MethodGet || len(bw.buf) > 0) {
+			h.Set("Etag", e.generate(bw.buf))
+		}
+		if bw.status == http.StatusOK {
+			switch check(r, h.Get("Etag"), h.Get("Last-Modified")) {
+			case http.StatusNotModified:
+				writeNotModified(w)
+				return
+			case http.StatusPreconditionFailed:
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+		}
+		bw.flushBuffer()
+	}
+}
+
+// generate returns an ETag of body.
+func (e *ETagger) generate(body []byte) string {
+	newHash := e.Hash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	h := newHash()
+	h.Write(body)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(h.Sum(nil)) + `"`
+	if e.Weak {
+		etag = "W/" + etag
+	}
+	return etag
+}
+
+// check evaluates preconditions of the GET or HEAD request r against the response
+// that has etag and lastModified in the order of RFC 7232 section 6.
+// check returns 0 if the response should be sent.
+func check(r *http.Request, etag, lastModified string) int {
+	if im := r.Header.Get("If-Match"); im != "" {
+		if !matchETag(im, etag, true) {
+			return http.StatusPreconditionFailed
+		}
+	} else if !checkUnmodifiedSince(r, lastModified) {
+		return http.StatusPreconditionFailed
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if matchETag(inm, etag, false) {
+			return http.StatusNotModified
+		}
+		return 0
+	}
+	if ims, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil {
+		if lm, err := http.ParseTime(lastModified); err == nil && !lm.Truncate(time.Second).After(ims) {
+			return http.StatusNotModified
+		}
+	}
+	return 0
+}
+
+// checkUnsafe evaluates If-Match and If-None-Match of r against the current state of the resource.
+func checkUnsafe(r *http.Request, etag string, found bool) bool {
+	if im := r.Header.Get("If-Match"); im != "" && (!found || !matchETag(im, etag, true)) {
+		return false
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" && found && matchETag(inm, etag, false) {
+		return false
+	}
+	return true
+}
+
+func checkUnmodifiedSince(r *http.Request, lastModified string) bool {
+	ius, err := http.ParseTime(r.Header.Get("If-Unmodified-Since"))
+	if err != nil {
+		return true
+	}
+	lm, err := http.ParseTime(lastModified)
+	if err != nil {
+		return true
+	}
+	return !lm.Truncate(time.Second).After(ius)
+}
+
+// writeNotModified writes "304 Not Modified" without the headers of the representation.
+func writeNotModified(w http.ResponseWriter) {
+	h := w.Header()
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	h.Del("Content-Encoding")
+	if h.Get("Etag") != "" {
+		h.Del("Last-Modified")
+	}
+	w.WriteHeader(http.StatusNotModified)
+}
@@ -0,0 +1,168 @@
+package etag_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/etag"
+)
+
+const lastModified = "Mon, 06 Jan 2014 00:00:00 GMT"
+
+func newTestHandler(t *testing.T, e *etag.ETagger) http.Handler {
+	mux := denco.NewMux()
+	handlers := mux.Wrap(e.Wrap,
+		mux.GET("/user/:id", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprintf(w, "user %s", params.Get("id"))
+		}),
+		mux.GET("/modified", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			w.Header().Set("Last-Modified", lastModified)
+			io.WriteString(w, "modified")
+		}),
+		mux.GET("/custom", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			w.Header().Set("ETag", `"custom"`)
+			io.WriteString(w, "custom")
+		}),
+		mux.GET("/error", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			http.Error(w, "error", http.StatusInternalServerError)
+		}),
+		mux.GET("/large", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			io.WriteString(w, strings.Repeat("a", 16))
+			io.WriteString(w, strings.Repeat("b", 16))
+		}),
+	)
+	handlers = append(handlers, mux.GET("/plain", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		io.WriteString(w, "plain")
+	}))
+	handler, err := mux.Build(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return handler
+}
+
+func get(h http.Handler, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestETagger_zeroValue(t *testing.T) {
+	h := newTestHandler(t, &etag.ETagger{})
+	for _, path := range []string{"/user/1", "/large"} {
+		if actual := get(h, path, nil).Header().Get("ETag"); actual == "" {
+			t.Errorf("ETag of %v => %q, want an ETag", path, actual)
+		}
+	}
+}
+
+func TestETagger(t *testing.T) {
+	e := etag.New()
+	e.MaxBodyBytes = 20
+	h := newTestHandler(t, e)
+	userETag := get(h, "/user/1", nil).Header().Get("ETag")
+	if !strings.HasPrefix(userETag, `"`) || userETag == get(h, "/user/2", nil).Header().Get("ETag") {
+		t.Fatalf("ETag of /user/1 => %q, want a strong ETag that differs from /user/2", userETag)
+	}
+	for _, v := range []struct {
+		path   string
+		header map[string]string
+		status int
+		body   string
+		etag   string
+	}{
+		{"/user/1", nil, 200, "user 1", userETag},
+		{"/user/1", map[string]string{"If-None-Match": userETag}, 304, "", userETag},
+		{"/user/1", map[string]string{"If-None-Match": `"other", W/` + userETag}, 304, "", userETag},
+		{"/user/1", map[string]string{"If-None-Match": "*"}, 304, "", userETag},
+		{"/user/1", map[string]string{"If-None-Match": `"other"`}, 200, "user 1", userETag},
+		{"/user/1", map[string]string{"If-Match": userETag}, 200, "user 1", userETag},
+		{"/user/1", map[string]string{"If-Match": "W/" + userETag}, 412, "", userETag},
+		{"/user/1", map[string]string{"If-Match": `"other"`}, 412, "", userETag},
+		{"/modified", map[string]string{"If-Modified-Since": lastModified}, 304, "", ""},
+		{"/modified", map[string]string{"If-Modified-Since": "Sun, 05 Jan 2014 00:00:00 GMT"}, 200, "modified", ""},
+		{"/modified", map[string]string{"If-Unmodified-Since": "Sun, 05 Jan 2014 00:00:00 GMT"}, 412, "", ""},
+		{"/custom", map[string]string{"If-None-Match": `"custom"`}, 304, "", `"custom"`},
+		{"/error", map[string]string{"If-None-Match": "*"}, 500, "error\n", ""},
+		{"/large", map[string]string{"If-None-Match": "*"}, 200, strings.Repeat("a", 16) + strings.Repeat("b", 16), ""},
+		{"/plain", map[string]string{"If-None-Match": "*"}, 200, "plain", ""},
+	} {
+		w := get(h, v.path, v.header)
+		if w.Code != v.status || w.Body.String() != v.body {
+			t.Errorf("GET %s with %v => %v %q, want %v %q", v.path, v.header, w.Code, w.Body.String(), v.status, v.body)
+		}
+		if actual := w.Header().Get("ETag"); v.etag != "" && actual != v.etag {
+			t.Errorf("GET %s with %v => ETag %q, want %q", v.path, v.header, actual, v.etag)
+		}
+		if w.Code == http.StatusNotModified && w.Header().Get("Content-Type") != "" {
+			t.Errorf("GET %s with %v => Content-Type %q in 304", v.path, v.header, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestETagger_Weak(t *testing.T) {
+	e := etag.New()
+	e.Weak = true
+	h := newTestHandler(t, e)
+	tag := get(h, "/user/1", nil).Header().Get("ETag")
+	if !strings.HasPrefix(tag, `W/"`) {
+		t.Fatalf("ETag => %q, want a weak ETag", tag)
+	}
+	if w := get(h, "/user/1", map[string]string{"If-None-Match": tag}); w.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: %s => %v, want %v", tag, w.Code, http.StatusNotModified)
+	}
+	if w := get(h, "/user/1", map[string]string{"If-Match": tag}); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("If-Match: %s => %v, want %v", tag, w.Code, http.StatusPreconditionFailed)
+	}
+}
+
+func TestETagger_Current(t *testing.T) {
+	e := etag.New()
+	e.Current = func(r *http.Request, params denco.Params) (string, bool) {
+		if params.Get("id") == "1" {
+			return `"v1"`, true
+		}
+		return "", false
+	}
+	mux := denco.NewMux()
+	handler, err := mux.Build([]denco.Handler{
+		mux.PUT("/user/:id", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			w.WriteHeader(http.StatusNoContent)
+		}).Wrap(e.Wrap),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path          string
+		header, value string
+		status        int
+	}{
+		{"/user/1", "If-Match", `"v1"`, 204},
+		{"/user/1", "If-Match", `"v0"`, 412},
+		{"/user/2", "If-Match", "*", 412},
+		{"/user/1", "If-None-Match", "*", 412},
+		{"/user/2", "If-None-Match", "*", 204},
+		{"/user/1", "", "", 204},
+	} {
+		req := httptest.NewRequest("PUT", v.path, nil)
+		if v.header != "" {
+			req.Header.Set(v.header, v.value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != v.status {
+			t.Errorf("PUT %s with %s: %s => %v, want %v", v.path, v.header, v.value, w.Code, v.status)
+		}
+	}
+}
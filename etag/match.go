@@ -0,0 +1,55 @@
+package etag
+
+import (
+	"strings"
+)
+
+// matchETag reports whether etag matches any of the entity-tags of header value list,
+// such as `"a", W/"b"` or "*". If strong is true, the strong comparison is used,
+// otherwise the weak comparison is used. See RFC 7232 section 2.3.2.
+func matchETag(list, etag string, strong bool) bool {
+	if strings.TrimSpace(list) == "*" {
+		return etag != ""
+	}
+	if etag == "" {
+		return false
+	}
+	for {
+		list = strings.TrimLeft(list, " \t,")
+		if list == "" {
+			return false
+		}
+		tag, rest, ok := scanETag(list)
+		if !ok {
+			return false
+		}
+		if equalETag(tag, etag, strong) {
+			return true
+		}
+		list = rest
+	}
+}
+
+// scanETag returns the first entity-tag of s and the rest of s.
+func scanETag(s string) (tag, rest string, ok bool) {
+	start := 0
+	if strings.HasPrefix(s, "W/") {
+		start = 2
+	}
+	if len(s[start:]) < 2 || s[start] != '"' {
+		return "", "", false
+	}
+	end := strings.IndexByte(s[start+1:], '"')
+	if end < 0 {
+		return "", "", false
+	}
+	end += start + 2
+	return s[:end], s[end:], true
+}
+
+func equalETag(a, b string, strong bool) bool {
+	if strong {
+		return a == b && !strings.HasPrefix(a, "W/")
+	}
+	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
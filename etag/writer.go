@@ -0,0 +1,80 @@
+package etag
+
+import (
+	"net/http"
+)
+
+// bufferWriter buffers the response to compute the ETag.
+// It stops buffering and writes the response as is if the body exceeds max or is flushed.
+type bufferWriter struct {
+	http.ResponseWriter
+
+	max    int
+	status int
+	buf    []byte
+
+	// passthrough reports whether the buffered response has been written.
+	passthrough bool
+}
+
+// WriteHeader implements http.ResponseWriter.WriteHeader.
+func (w *bufferWriter) WriteHeader(code int) {
+	if w.passthrough || code < 200 {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+// Write implements http.ResponseWriter.Write.
+func (w *bufferWriter) Write(b []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if len(w.buf)+len(b) > w.max {
+		if err := w.flushBuffer(); err != nil {
+			return 0, err
+		}
+		return w.ResponseWriter.Write(b)
+	}
+	w.buf = append(w.buf, b...)
+	return len(b), nil
+}
+
+// Flush implements http.Flusher.
+// The response is sent without ETag once it is flushed.
+func (w *bufferWriter) Flush() {
+	w.flushBuffer()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+func (w *bufferWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// flushBuffer writes the status and the buffered body, and stops buffering.
+func (w *bufferWriter) flushBuffer() error {
+	if w.passthrough {
+		return nil
+	}
+	w.passthrough = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
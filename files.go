@@ -0,0 +1,164 @@
+package denco
+
+import (
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/naoina/denco/internal/httputil"
+)
+
+// IndexFile is a name of the file that served for a directory by Mux.ServeFiles.
+const IndexFile = "index.html"
+
+// ServeFiles returns handlers that serve files from root for GET and HEAD requests
+// for path and under path. For example, when path is "/static", the request for
+// "/static/css/style.css" is served by the file "/css/style.css" of root.
+// Use http.FS to serve files from fs.FS.
+//
+// The request path is cleaned, so it cannot refer to files outside of root.
+// The request for a directory is served by the IndexFile in the directory, and a directory
+// listing is never served. The response has ETag and Last-Modified headers, and
+// conditional and range requests are handled by http.ServeContent.
+// If the client accepts "br" or "gzip" encoding and there is a precompressed file that
+// has ".br" or ".gz" suffix, that file is served instead. The encoding that has the highest
+// quality value in Accept-Encoding is preferred, and "br" is preferred to "gzip" on ties.
+func (m *Mux) ServeFiles(path string, root http.FileSystem) []Handler {
+	srv := &fileServer{root: root}
+	prefix := strings.TrimSuffix(path, "/")
+	var handlers []Handler
+	for _, p := range []string{prefix + "/", prefix + "/*" + fileParamName, prefix} {
+		if p == "" {
+			continue
+		}
+		handlers = append(handlers, m.GET(p, srv.serve), m.HEAD(p, srv.serve))
+	}
+	return handlers
+}
+
+// fileParamName is a name of the wildcard parameter of handlers that returned by Mux.ServeFiles.
+const fileParamName = "filepath"
+
+// precompressedEncoding represents a content coding and a suffix of precompressed files.
+type precompressedEncoding struct {
+	coding, suffix string
+}
+
+// precompressedEncodings is a list of precompressed encodings in order of preference.
+// The order is used to break ties of quality values in Accept-Encoding.
+var precompressedEncodings = []precompressedEncoding{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+type fileServer struct {
+	root http.FileSystem
+}
+
+func (s *fileServer) serve(w http.ResponseWriter, r *http.Request, params Params) {
+	name := path.Clean("/" + params.Get(fileParamName))
+	f, fi, err := s.open(name)
+	if err != nil {
+		serveFileError(w, err)
+		return
+	}
+	defer f.Close()
+	if fi.IsDir() {
+		if !strings.HasSuffix(r.URL.Path, "/") {
+			http.Redirect(w, r, path.Base(r.URL.Path)+"/", http.StatusMovedPermanently)
+			return
+		}
+		f.Close()
+		name = path.Join(name, IndexFile)
+		if f, fi, err = s.open(name); err != nil {
+			serveFileError(w, err)
+			return
+		}
+		defer f.Close()
+		if fi.IsDir() {
+			serveFileError(w, os.ErrNotExist)
+			return
+		}
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	for _, enc := range acceptedEncodings(r) {
+		cf, cfi, err := s.open(name + enc.suffix)
+		if err != nil || cfi.IsDir() {
+			continue
+		}
+		defer cf.Close()
+		ctype := mime.TypeByExtension(path.Ext(name))
+		if ctype == "" {
+			ctype = "application/octet-stream"
+		}
+		w.Header().Set("Content-Type", ctype)
+		w.Header().Set("Content-Encoding", enc.coding)
+		w.Header().Set("ETag", fileETag(cfi, enc.coding))
+		http.ServeContent(w, r, name, cfi.ModTime(), cf)
+		return
+	}
+	w.Header().Set("ETag", fileETag(fi, ""))
+	http.ServeContent(w, r, name, fi.ModTime(), f)
+}
+
+func (s *fileServer) open(name string) (http.File, os.FileInfo, error) {
+	f, err := s.root.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, fi, nil
+}
+
+// fileETag returns a strong ETag of the file from the size and modification time.
+func fileETag(fi os.FileInfo, coding string) string {
+	etag := strconv.FormatInt(fi.Size(), 16) + "-" + strconv.FormatInt(fi.ModTime().UnixNano(), 16)
+	if coding != "" {
+		etag += "-" + coding
+	}
+	return `"` + etag + `"`
+}
+
+// acceptedEncodings returns the precompressed encodings that Accept-Encoding header of r accepts,
+// in descending order of the quality values.
+func acceptedEncodings(r *http.Request) []precompressedEncoding {
+	var encs []precompressedEncoding
+	qs := make(map[string]float64)
+	for _, enc := range precompressedEncodings {
+		if q := httputil.EncodingQuality(r.Header, enc.coding); q > 0 {
+			encs = append(encs, enc)
+			qs[enc.coding] = q
+		}
+	}
+	sort.SliceStable(encs, func(i, j int) bool {
+		return qs[encs[i].coding] > qs[encs[j].coding]
+	})
+	return encs
+}
+
+func serveFileError(w http.ResponseWriter, err error) {
+	switch {
+	case os.IsNotExist(err) || isInvalidPath(err):
+		http.Error(w, "404 page not found", http.StatusNotFound)
+	case os.IsPermission(err):
+		http.Error(w, "403 Forbidden", http.StatusForbidden)
+	default:
+		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func isInvalidPath(err error) bool {
+	if e, ok := err.(*fs.PathError); ok {
+		return e.Err == fs.ErrInvalid
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package denco_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/naoina/denco"
+)
+
+func TestMux_ServeFiles(t *testing.T) {
+	modTime := time.Date(2014, 1, 6, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"css/style.css":    {Data: []byte("body {}"), ModTime: modTime},
+		"css/style.css.br": {Data: []byte("br"), ModTime: modTime},
+		"css/style.css.gz": {Data: []byte("gz"), ModTime: modTime},
+		"js/app.js":        {Data: []byte("app"), ModTime: modTime},
+		"js/app.js.gz":     {Data: []byte("gz"), ModTime: modTime},
+		"index.html":       {Data: []byte("index"), ModTime: modTime},
+		"docs/index.html":  {Data: []byte("docs"), ModTime: modTime},
+		"empty/a.txt":      {Data: []byte("a"), ModTime: modTime},
+	}
+	mux := denco.NewMux()
+	handler, err := mux.Build(mux.ServeFiles("/static", http.FS(fsys)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path, acceptEncoding        string
+		status                      int
+		body, encoding, contentType string
+	}{
+		{"/static/css/style.css", "", 200, "body {}", "", "text/css; charset=utf-8"},
+		{"/static/css/style.css", "gzip, br", 200, "br", "br", "text/css; charset=utf-8"},
+		{"/static/css/style.css", "gzip, br;q=0", 200, "gz", "gzip", "text/css; charset=utf-8"},
+		{"/static/css/style.css", "gzip;q=1, br;q=0.1", 200, "gz", "gzip", "text/css; charset=utf-8"},
+		{"/static/css/style.css", "gzip;q=0.5, br;q=0.5", 200, "br", "br", "text/css; charset=utf-8"},
+		{"/static/js/app.js", "br", 200, "app", "", "text/javascript; charset=utf-8"},
+		{"/static/js/app.js", "*", 200, "gz", "gzip", "text/javascript; charset=utf-8"},
+		{"/static/js/app.js", "gzip;q=0, *", 200, "app", "", "text/javascript; charset=utf-8"},
+		{"/static/", "", 200, "index", "", "text/html; charset=utf-8"},
+		{"/static", "", 301, "", "", ""},
+		{"/static/docs/", "", 200, "docs", "", "text/html; charset=utf-8"},
+		{"/static/docs", "", 301, "", "", ""},
+		{"/static/empty/", "", 404, "404 page not found\n", "", "text/plain; charset=utf-8"},
+		{"/static/../../etc/passwd", "", 404, "404 page not found\n", "", "text/plain; charset=utf-8"},
+		{"/static/missing.css", "", 404, "404 page not found\n", "", "text/plain; charset=utf-8"},
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.URL.Path = v.path
+		if v.acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", v.acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != v.status {
+			t.Errorf(`GET "%s" => %#v, want %#v`, v.path, w.Code, v.status)
+			continue
+		}
+		if v.status == 301 {
+			continue
+		}
+		if actual := w.Body.String(); actual != v.body {
+			t.Errorf(`GET "%s" => body %#v, want %#v`, v.path, actual, v.body)
+		}
+		if actual := w.Header().Get("Content-Encoding"); actual != v.encoding {
+			t.Errorf(`GET "%s" => Content-Encoding %#v, want %#v`, v.path, actual, v.encoding)
+		}
+		if actual := w.Header().Get("Content-Type"); actual != v.contentType {
+			t.Errorf(`GET "%s" => Content-Type %#v, want %#v`, v.path, actual, v.contentType)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/static/css/style.css", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Last-Modified") != modTime.Format(http.TimeFormat) {
+		t.Fatalf(`GET "/static/css/style.css" => ETag %#v, Last-Modified %#v`, etag, w.Header().Get("Last-Modified"))
+	}
+	for _, header := range []map[string]string{
+		{"If-None-Match": etag},
+		{"If-Modified-Since": modTime.Format(http.TimeFormat)},
+	} {
+		req := httptest.NewRequest("GET", "/static/css/style.css", nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusNotModified {
+			t.Errorf(`GET "/static/css/style.css" %v => %#v, want %#v`, header, w.Code, http.StatusNotModified)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package denco
+
+import "io"
+
+// RecordOf represents a record data for RouterOf construction.
+type RecordOf[T any] struct {
+	// Key for router construction.
+	Key string
+
+	// Result value for Key.
+	Value T
+
+	// Meta is arbitrary metadata of the record.
+	Meta Meta
+}
+
+// NewRecordOf returns a new RecordOf.
+func NewRecordOf[T any](key string, value T) RecordOf[T] {
+	return RecordOf[T]{
+		Key:   key,
+		Value: value,
+	}
+}
+
+// RouterOf represents a URL router that associates keys with values of type T.
+// RouterOf shares the implementation with Router, but values are held in a slice of T
+// and looked up by the index of the record, so they are neither boxed nor asserted.
+type RouterOf[T any] struct {
+	// SizeHint expects the maximum number of path parameters in records to Build.
+	// See Router.SizeHint.
+	SizeHint int
+
+	// ParamCharacter is a special character for path parameter.
+	// See Router.ParamCharacter.
+	ParamCharacter byte
+
+	// WildcardCharacter is a special character for wildcard path parameter.
+	// See Router.WildcardCharacter.
+	WildcardCharacter byte
+
+	// TerminationCharacter is a special character for end of path.
+	// See Router.TerminationCharacter.
+	TerminationCharacter byte
+
+	// Separator is a character that separates the segments of a path.
+	// See Router.Separator.
+	Separator byte
+
+	router *Router
+	values []T
+}
+
+// NewOf returns a new RouterOf.
+func NewOf[T any]() *RouterOf[T] {
+	rt := New()
+	return &RouterOf[T]{
+		SizeHint:             rt.SizeHint,
+		ParamCharacter:       rt.ParamCharacter,
+		WildcardCharacter:    rt.WildcardCharacter,
+		TerminationCharacter: rt.TerminationCharacter,
+		Separator:            rt.Separator,
+		router:               rt,
+	}
+}
+
+// Lookup returns a value and path parameters that associated with path.
+// See Router.Lookup for details.
+func (rt *RouterOf[T]) Lookup(path string) (value T, params Params, found bool) {
+	value, _, params, found = rt.LookupMeta(path)
+	return value, params, found
+}
+
+// LookupMeta is the same as Lookup, but also returns the metadata of the record that associated with path.
+func (rt *RouterOf[T]) LookupMeta(path string) (value T, meta Meta, params Params, found bool) {
+	nd, params, found := rt.router.lookup(path)
+	if !found {
+		return value, nil, nil, false
+	}
+	return rt.values[nd.index], nd.meta, params, true
+}
+
+// Build builds URL router from records.
+// Build replaces the records that built by the previous Build.
+func (rt *RouterOf[T]) Build(records []RecordOf[T]) error {
+	values := make([]T, len(records))
+	recs := make([]RecordMeta, len(records))
+	for i, r := range records {
+		values[i] = r.Value
+		recs[i] = RecordMeta{Record: Record{Key: r.Key}, Meta: r.Meta}
+	}
+	router := New()
+	router.SizeHint = rt.SizeHint
+	router.ParamCharacter = rt.ParamCharacter
+	router.WildcardCharacter = rt.WildcardCharacter
+	router.TerminationCharacter = rt.TerminationCharacter
+	router.Separator = rt.Separator
+	if err := router.BuildMeta(recs); err != nil {
+		return err
+	}
+	rt.router, rt.values = router, values
+	return nil
+}
+
+// WriteTree writes a human readable representation of the routing table to w.
+// See Router.WriteTree for details.
+func (rt *RouterOf[T]) WriteTree(w io.Writer) error {
+	return rt.router.writeTree(w, rt.value)
+}
+
+// WriteDOT writes the routing table to w as a graph of Graphviz DOT language.
+// See Router.WriteDOT for details.
+func (rt *RouterOf[T]) WriteDOT(w io.Writer) error {
+	return rt.router.writeDOT(w, rt.value)
+}
+
+func (rt *RouterOf[T]) value(nd *node) interface{} {
+	return rt.values[nd.index]
+}
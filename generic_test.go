@@ -0,0 +1,105 @@
+package denco_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/naoina/denco"
+)
+
+type testRoute struct {
+	name string
+}
+
+func TestRouterOf_Lookup(t *testing.T) {
+	r := denco.NewOf[*testRoute]()
+	if err := r.Build([]denco.RecordOf[*testRoute]{
+		{Key: "/", Value: &testRoute{"root"}},
+		{Key: "/user/:id", Value: &testRoute{"user"}},
+		denco.NewRecordOf("/static/*filepath", &testRoute{"static"}),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path   string
+		value  *testRoute
+		params denco.Params
+		found  bool
+	}{
+		{"/", &testRoute{"root"}, nil, true},
+		{"/user/alice", &testRoute{"user"}, denco.Params{{Name: "id", Value: "alice"}}, true},
+		{"/static/a/b.css", &testRoute{"static"}, denco.Params{{Name: "filepath", Value: "a/b.css"}}, true},
+		{"/missing", nil, nil, false},
+	} {
+		value, params, found := r.Lookup(v.path)
+		if !reflect.DeepEqual(value, v.value) || !reflect.DeepEqual(params, v.params) || found != v.found {
+			t.Errorf("RouterOf.Lookup(%q) => (%#v, %#v, %#v), want (%#v, %#v, %#v)", v.path, value, params, found, v.value, v.params, v.found)
+		}
+	}
+
+	if value, meta, _, found := r.LookupMeta("/"); value.name != "root" || meta != nil || !found {
+		t.Errorf("RouterOf.LookupMeta(%q) => (%#v, %#v, %#v), want (%#v, %#v, %#v)", "/", value, meta, found, &testRoute{"root"}, denco.Meta(nil), true)
+	}
+
+	ints := denco.NewOf[int]()
+	ints.Separator = '.'
+	if err := ints.Build([]denco.RecordOf[int]{{Key: "a.:b", Value: 42, Meta: denco.Meta{"owner": "team-a"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if value, meta, _, found := ints.LookupMeta("a.c"); value != 42 || meta.String("owner") != "team-a" || !found {
+		t.Errorf("RouterOf.LookupMeta(%q) => (%#v, %#v, %#v), want (%#v, %#v, %#v)", "a.c", value, meta, found, 42, denco.Meta{"owner": "team-a"}, true)
+	}
+	if value, _, found := ints.Lookup("a.c.d"); value != 0 || found {
+		t.Errorf("RouterOf.Lookup(%q) => (%#v, %#v), want (%#v, %#v)", "a.c.d", value, found, 0, false)
+	}
+}
+
+func TestRouterOf_Build_twice(t *testing.T) {
+	r := denco.NewOf[string]()
+	if err := r.Build([]denco.RecordOf[string]{denco.NewRecordOf("/a", "alpha"), denco.NewRecordOf("/b", "bravo")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Build([]denco.RecordOf[string]{denco.NewRecordOf("/c", "charlie")}); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path  string
+		value string
+		found bool
+	}{
+		{"/a", "", false},
+		{"/b", "", false},
+		{"/c", "charlie", true},
+	} {
+		if value, _, found := r.Lookup(v.path); value != v.value || found != v.found {
+			t.Errorf("RouterOf.Lookup(%q) => (%#v, %#v), want (%#v, %#v)", v.path, value, found, v.value, v.found)
+		}
+	}
+}
+
+func TestRouterOf_WriteTree(t *testing.T) {
+	r := denco.NewOf[string]()
+	if err := r.Build([]denco.RecordOf[string]{
+		denco.NewRecordOf("/a", "alpha"),
+		denco.NewRecordOf("/user/:id", "user"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := r.WriteTree(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := "/a => alpha\n/user/\n  : => user [id]\n"
+	if actual := buf.String(); actual != expected {
+		t.Errorf("RouterOf.WriteTree => %q, want %q", actual, expected)
+	}
+	buf.Reset()
+	if err := r.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if actual := buf.String(); !strings.Contains(actual, `label="alpha"`) || !strings.Contains(actual, `label=":\nuser [id]"`) {
+		t.Errorf("RouterOf.WriteDOT => %q, want labels of values", actual)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/naoina/denco
+
+go 1.18
@@ -0,0 +1,93 @@
+// Package bind provides the conversion of strings into the values of struct fields
+// that is shared by denco.Params.Bind and the binding package.
+package bind
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PathTag is the name of the struct tag that specifies the name of the path parameter.
+const PathTag = "path"
+
+// Tag represents the value of a struct tag such as "id,required".
+type Tag struct {
+	// Name is the name of the value. It defaults to the name of the field.
+	Name string
+
+	// Required reports whether the tag has "required" option.
+	Required bool
+}
+
+// LookupTag returns the Tag of key of f.
+// LookupTag returns false if f has no tag of key, the tag is "-" or f is unexported.
+// Options that are unknown are ignored.
+func LookupTag(f reflect.StructField, key string) (Tag, bool) {
+	s, found := f.Tag.Lookup(key)
+	if !found || s == "-" || f.PkgPath != "" {
+		return Tag{}, false
+	}
+	opts := strings.Split(s, ",")
+	tag := Tag{Name: opts[0]}
+	if tag.Name == "" {
+		tag.Name = f.Name
+	}
+	for _, opt := range opts[1:] {
+		if strings.TrimSpace(opt) == "required" {
+			tag.Required = true
+		}
+	}
+	return tag, true
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// SetValue sets s to fv with conversion to the type of fv.
+// fv must be settable, and of bool, string, integer, floating-point, a type that
+// implements encoding.TextUnmarshaler or a pointer to them.
+// The error message describes the expected value, e.g. "must be an integer in range of int".
+func SetValue(fv reflect.Value, s string) error {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return SetValue(fv.Elem(), s)
+	}
+	if reflect.PtrTo(fv.Type()).Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+		fv.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be an integer in range of %v", fv.Type())
+		}
+		fv.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a non-negative integer in range of %v", fv.Type())
+		}
+		fv.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		fv.SetFloat(v)
+	default:
+		return fmt.Errorf("unsupported type %v", fv.Type())
+	}
+	return nil
+}
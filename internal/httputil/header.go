@@ -0,0 +1,47 @@
+package httputil
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ParseQuality parses an element of the header that has quality value such as "gzip;q=0.8".
+// The returned value is lowercased.
+func ParseQuality(s string) (value string, q float64) {
+	q = 1
+	value = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexByte(value, ';'); i >= 0 {
+		for _, p := range strings.Split(value[i+1:], ";") {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if f, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					q = f
+				}
+			}
+		}
+		value = strings.TrimSpace(value[:i])
+	}
+	return value, q
+}
+
+// EncodingQuality returns the quality value of the content coding in Accept-Encoding header of h.
+// An explicit entry of coding takes precedence over "*". EncodingQuality returns 0 if coding is not accepted.
+func EncodingQuality(h http.Header, coding string) float64 {
+	var q, wildcard float64
+	found := false
+	for _, v := range h["Accept-Encoding"] {
+		for _, item := range strings.Split(v, ",") {
+			switch c, cq := ParseQuality(item); c {
+			case coding:
+				q, found = cq, true
+			case "*":
+				wildcard = cq
+			}
+		}
+	}
+	if found {
+		return q
+	}
+	return wildcard
+}
@@ -0,0 +1,80 @@
+// Package httputil provides HTTP utilities shared by the middleware packages of denco.
+package httputil
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter wraps http.ResponseWriter to record the status code and the number of bytes written.
+type ResponseWriter struct {
+	http.ResponseWriter
+
+	// Status is the status code of the response.
+	// It is zero until WriteHeader or Write is called.
+	Status int
+
+	// Bytes is the number of bytes of the body written.
+	Bytes int64
+}
+
+// NewResponseWriter returns a new ResponseWriter that wraps w.
+func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	return &ResponseWriter{ResponseWriter: w}
+}
+
+// WriteHeader implements http.ResponseWriter.WriteHeader.
+func (w *ResponseWriter) WriteHeader(code int) {
+	if w.Status == 0 {
+		w.Status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.Write.
+func (w *ResponseWriter) Write(b []byte) (int, error) {
+	if w.Status == 0 {
+		w.Status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.Bytes += int64(n)
+	return n, err
+}
+
+// StatusCode returns Status, or http.StatusOK if nothing has been written.
+func (w *ResponseWriter) StatusCode() int {
+	if w.Status == 0 {
+		return http.StatusOK
+	}
+	return w.Status
+}
+
+// Flush implements http.Flusher if the underlying http.ResponseWriter supports it.
+func (w *ResponseWriter) Flush() {
+	if w.Status == 0 {
+		w.Status = http.StatusOK
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker.
+// It returns an error if the underlying http.ResponseWriter doesn't support it.
+func (w *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("http.Hijacker is not supported by %T", w.ResponseWriter)
+	}
+	if w.Status == 0 {
+		w.Status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,34 @@
+package httputil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/naoina/denco/internal/httputil"
+)
+
+func TestResponseWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := httputil.NewResponseWriter(rec)
+	if w.StatusCode() != http.StatusOK {
+		t.Errorf("StatusCode() before write => %v, want %v", w.StatusCode(), http.StatusOK)
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte("hello"))
+	w.Write([]byte(" world"))
+	w.Flush()
+	if w.Status != http.StatusCreated || w.Bytes != 11 {
+		t.Errorf("ResponseWriter => (%v, %v), want (%v, %v)", w.Status, w.Bytes, http.StatusCreated, 11)
+	}
+	if !rec.Flushed || rec.Body.String() != "hello world" {
+		t.Errorf("underlying ResponseWriter => (%v, %q), want (%v, %q)", rec.Flushed, rec.Body.String(), true, "hello world")
+	}
+	if _, _, err := w.Hijack(); err == nil {
+		t.Errorf("Hijack() => nil, want error")
+	}
+	if w.Unwrap() != rec {
+		t.Errorf("Unwrap() => %v, want %v", w.Unwrap(), rec)
+	}
+}
@@ -0,0 +1,101 @@
+package denco
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// WithTimeout returns a copy of the handler that Timeout is set to d.
+func (h Handler) WithTimeout(d time.Duration) Handler {
+	h.Timeout = d
+	return h
+}
+
+// WithMaxBodyBytes returns a copy of the handler that MaxBodyBytes is set to n.
+func (h Handler) WithMaxBodyBytes(n int64) Handler {
+	h.MaxBodyBytes = n
+	return h
+}
+
+// WithMaxHeaderBytes returns a copy of the handler that MaxHeaderBytes is set to n.
+func (h Handler) WithMaxHeaderBytes(n int) Handler {
+	h.MaxHeaderBytes = n
+	return h
+}
+
+// limits is the default limits of handlers.
+type limits struct {
+	timeout        time.Duration
+	maxBodyBytes   int64
+	maxHeaderBytes int
+}
+
+// serve calls the function of h within the limits of h.
+// The limits of h that are zero fall back to l.
+func (l limits) serve(w http.ResponseWriter, r *http.Request, h *Handler, params Params) {
+	if n := limitOf(int64(h.MaxHeaderBytes), int64(l.maxHeaderBytes)); n > 0 && headerSize(r) > n {
+		http.Error(w, http.StatusText(http.StatusRequestHeaderFieldsTooLarge), http.StatusRequestHeaderFieldsTooLarge)
+		return
+	}
+	if n := limitOf(h.MaxBodyBytes, l.maxBodyBytes); n > 0 && r.Body != nil {
+		if r.ContentLength > n {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.Body = http.MaxBytesReader(w, r.Body, n)
+		r = r2
+	}
+	if d := limitOf(int64(h.Timeout), int64(l.timeout)); d > 0 {
+		serveTimeout(w, r, h, params, time.Duration(d))
+		return
+	}
+	h.Func(w, r, params)
+}
+
+// serveTimeout calls the function of h with the time limit d.
+// The function is called in another goroutine by http.TimeoutHandler, so it is given a copy
+// of the Route in the context of r. If the request is routed again in the function, such as
+// by a mounted Mux, the copy is merged back into the Route only if the function returns within d.
+func serveTimeout(w http.ResponseWriter, r *http.Request, h *Handler, params Params, d time.Duration) {
+	route := RouteFromContext(r.Context())
+	inner, rerouted := route.fork()
+	done := make(chan struct{})
+	inTime := false
+	http.TimeoutHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		h.Func(w, r, params)
+		inTime = r.Context().Err() == nil
+	}), d, "").ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), routeContextKey{}, inner)))
+	select {
+	case <-done:
+		if inTime && *rerouted {
+			route.merge(inner)
+		}
+	default:
+	}
+}
+
+// limitOf returns v if v isn't zero, otherwise def.
+func limitOf(v, def int64) int64 {
+	if v != 0 {
+		return v
+	}
+	return def
+}
+
+// headerSize returns the approximate size of the request line and the header of r on the wire.
+func headerSize(r *http.Request) int64 {
+	size := len(r.Method) + len(r.RequestURI) + len(r.Proto) + len(" \r\n ")
+	if r.Host != "" {
+		size += len("Host: \r\n") + len(r.Host)
+	}
+	for key, values := range r.Header {
+		for _, v := range values {
+			size += len(key) + len(": \r\n") + len(v)
+		}
+	}
+	return int64(size)
+}
@@ -0,0 +1,119 @@
+package denco_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/naoina/denco"
+)
+
+func TestMux_Timeout(t *testing.T) {
+	mux := denco.NewMux()
+	mux.Timeout = 10 * time.Millisecond
+	wait := func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(50 * time.Millisecond):
+			fmt.Fprint(w, "done")
+		}
+	}
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/api", wait),
+		mux.GET("/upload/*path", wait).WithTimeout(time.Second),
+		mux.GET("/stream", wait).WithTimeout(-1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path     string
+		status   int
+		expected string
+	}{
+		{"/api", http.StatusServiceUnavailable, ""},
+		{"/upload/file", http.StatusOK, "done"},
+		{"/stream", http.StatusOK, "done"},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", v.path, nil))
+		if w.Code != v.status || (v.expected != "" && w.Body.String() != v.expected) {
+			t.Errorf("GET %s => %v %q, want %v %q", v.path, w.Code, w.Body.String(), v.status, v.expected)
+		}
+	}
+}
+
+func TestMux_MaxBodyBytes(t *testing.T) {
+	mux := denco.NewMux()
+	mux.MaxBodyBytes = 8
+	read := func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		fmt.Fprintf(w, "%d bytes", len(body))
+	}
+	handler, err := mux.Build([]denco.Handler{
+		mux.POST("/api", read),
+		mux.POST("/upload/*path", read).WithMaxBodyBytes(1 << 20),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path          string
+		body          string
+		contentLength bool
+		status        int
+		expected      string
+	}{
+		{"/api", "12345678", true, http.StatusOK, "8 bytes"},
+		{"/api", "123456789", true, http.StatusRequestEntityTooLarge, "Request Entity Too Large\n"},
+		{"/api", "123456789", false, http.StatusRequestEntityTooLarge, "http: request body too large\n"},
+		{"/upload/file", strings.Repeat("a", 1024), true, http.StatusOK, "1024 bytes"},
+	} {
+		req := httptest.NewRequest("POST", v.path, strings.NewReader(v.body))
+		if !v.contentLength {
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != v.status || w.Body.String() != v.expected {
+			t.Errorf("POST %s with %d bytes => %v %q, want %v %q", v.path, len(v.body), w.Code, w.Body.String(), v.status, v.expected)
+		}
+	}
+}
+
+func TestMux_MaxHeaderBytes(t *testing.T) {
+	mux := denco.NewMux()
+	mux.MaxHeaderBytes = 128
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/api", testHandlerFunc),
+		mux.GET("/upload/*path", testHandlerFunc).WithMaxHeaderBytes(-1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path   string
+		cookie string
+		status int
+	}{
+		{"/api", "a=b", http.StatusOK},
+		{"/api", strings.Repeat("a", 128), http.StatusRequestHeaderFieldsTooLarge},
+		{"/upload/file", strings.Repeat("a", 128), http.StatusOK},
+	} {
+		req := httptest.NewRequest("GET", v.path, nil)
+		req.Header.Set("Cookie", v.cookie)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != v.status {
+			t.Errorf("GET %s with cookie %q => %v, want %v", v.path, v.cookie, w.Code, v.status)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package denco
+
+import (
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Matcher reports whether the request satisfies a condition.
+// Matchers are evaluated after the handlers have been found by the method and path.
+type Matcher func(r *http.Request) bool
+
+// MatchHeader returns a Matcher that matches when any value of the header name equals to value.
+func MatchHeader(name, value string) Matcher {
+	name = http.CanonicalHeaderKey(name)
+	return func(r *http.Request) bool {
+		for _, v := range r.Header[name] {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchHeaderRegexp returns a Matcher that matches when any value of the header name matches re.
+func MatchHeaderRegexp(name string, re *regexp.Regexp) Matcher {
+	name = http.CanonicalHeaderKey(name)
+	return func(r *http.Request) bool {
+		for _, v := range r.Header[name] {
+			if re.MatchString(v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchQuery returns a Matcher that matches when the query parameter name is present.
+func MatchQuery(name string) Matcher {
+	return func(r *http.Request) bool {
+		_, found := r.URL.Query()[name]
+		return found
+	}
+}
+
+// MatchQueryValue returns a Matcher that matches when any value of the query parameter name equals to value.
+func MatchQueryValue(name, value string) Matcher {
+	return func(r *http.Request) bool {
+		for _, v := range r.URL.Query()[name] {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchContentType returns a Matcher that matches when the media type of Content-Type header
+// is one of mediaTypes. A media type may be a wildcard subtype such as "text/*".
+func MatchContentType(mediaTypes ...string) Matcher {
+	return func(r *http.Request) bool {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			return false
+		}
+		for _, t := range mediaTypes {
+			t = strings.ToLower(t)
+			if t == mediaType || strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, t[:len(t)-1]) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchScheme returns a Matcher that matches when the scheme of the request is scheme.
+// The scheme is taken from the URL of the request if it is absolute, otherwise "https" if
+// the request was received on TLS connection, or "http".
+func MatchScheme(scheme string) Matcher {
+	scheme = strings.ToLower(scheme)
+	return func(r *http.Request) bool {
+		return requestScheme(r) == scheme
+	}
+}
+
+func requestScheme(r *http.Request) string {
+	switch {
+	case r.URL.Scheme != "":
+		return strings.ToLower(r.URL.Scheme)
+	case r.TLS != nil:
+		return "https"
+	}
+	return "http"
+}
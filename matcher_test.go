@@ -0,0 +1,47 @@
+package denco_test
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/naoina/denco"
+)
+
+func TestMatchers(t *testing.T) {
+	req := httptest.NewRequest("POST", "/?page=1&debug", nil)
+	req.Header.Add("Accept", "text/html")
+	req.Header.Add("Accept", "application/vnd.api.v2+json")
+	req.Header.Set("Content-Type", "Application/JSON; charset=utf-8")
+	tlsReq := httptest.NewRequest("GET", "/", nil)
+	tlsReq.TLS = &tls.ConnectionState{}
+	for _, v := range []struct {
+		name     string
+		matcher  denco.Matcher
+		expected bool
+	}{
+		{"MatchHeader", denco.MatchHeader("accept", "application/vnd.api.v2+json"), true},
+		{"MatchHeader", denco.MatchHeader("Accept", "application/json"), false},
+		{"MatchHeaderRegexp", denco.MatchHeaderRegexp("Accept", regexp.MustCompile(`\.v2\+`)), true},
+		{"MatchHeaderRegexp", denco.MatchHeaderRegexp("Accept", regexp.MustCompile(`\.v3\+`)), false},
+		{"MatchQuery", denco.MatchQuery("debug"), true},
+		{"MatchQuery", denco.MatchQuery("sort"), false},
+		{"MatchQueryValue", denco.MatchQueryValue("page", "1"), true},
+		{"MatchQueryValue", denco.MatchQueryValue("page", "2"), false},
+		{"MatchContentType", denco.MatchContentType("text/plain", "application/json"), true},
+		{"MatchContentType", denco.MatchContentType("application/*"), true},
+		{"MatchContentType", denco.MatchContentType("text/*"), false},
+		{"MatchScheme", denco.MatchScheme("http"), true},
+		{"MatchScheme", denco.MatchScheme("https"), false},
+	} {
+		actual := v.matcher(req)
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("%s(...)(%v) => %v, want %v", v.name, req.URL, actual, v.expected)
+		}
+	}
+	if actual := denco.MatchScheme("HTTPS")(tlsReq); !actual {
+		t.Errorf("MatchScheme(%q) with TLS => %v, want %v", "HTTPS", actual, true)
+	}
+}
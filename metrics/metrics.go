@@ -0,0 +1,252 @@
+// Package metrics instruments http.Handlers built by denco.Mux and exposes
+// the metrics in the Prometheus text exposition format.
+//
+//	m := metrics.New()
+//	handler, _ := mux.Build(handlers)
+//	http.Handle("/", m.Middleware(handler))
+//	http.Handle("/metrics", m)
+//
+// Requests are labeled by the method and the routing path of the matched route
+// such as "/user/:id", so that the cardinality of the metrics is bounded by the routes.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/internal/httputil"
+)
+
+// UnmatchedRoute is the route label of requests that no route matched.
+const UnmatchedRoute = "unmatched"
+
+var (
+	// DefaultDurationBuckets is default buckets of the request duration histogram in seconds.
+	DefaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+	// DefaultSizeBuckets is default buckets of the response size histogram in bytes.
+	DefaultSizeBuckets = []float64{100, 1000, 10000, 100000, 1e6, 1e7}
+)
+
+// Metrics collects metrics of HTTP requests.
+type Metrics struct {
+	// Namespace is a prefix of metric names, e.g. "myapp" makes "myapp_http_requests_total".
+	Namespace string
+
+	// DurationBuckets is upper bounds of buckets of the request duration histogram in seconds.
+	DurationBuckets []float64
+
+	// SizeBuckets is upper bounds of buckets of the response size histogram in bytes.
+	SizeBuckets []float64
+
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	durations map[routeKey]*histogram
+	sizes     map[routeKey]*histogram
+	inFlight  map[routeKey]int64
+
+	now func() time.Time
+}
+
+type routeKey struct {
+	method, route string
+}
+
+type requestKey struct {
+	routeKey
+	code int
+}
+
+// New returns a new Metrics with default buckets.
+func New() *Metrics {
+	return &Metrics{
+		DurationBuckets: DefaultDurationBuckets,
+		SizeBuckets:     DefaultSizeBuckets,
+		requests:        make(map[requestKey]uint64),
+		durations:       make(map[routeKey]*histogram),
+		sizes:           make(map[routeKey]*histogram),
+		inFlight:        make(map[routeKey]int64),
+		now:             time.Now,
+	}
+}
+
+// Middleware returns a http.Handler that records metrics of requests to next.
+// A request in flight is counted as the unmatched route until it is routed by next.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := routeKey{method: normalizeMethod(r.Method), route: UnmatchedRoute}
+		m.mu.Lock()
+		m.inFlight[key]++
+		m.mu.Unlock()
+		r, _ = denco.TrackRouteFunc(r, func(route *denco.Route) {
+			routed := newRouteKey(r.Method, route)
+			m.mu.Lock()
+			m.inFlight[key]--
+			m.inFlight[routed]++
+			m.mu.Unlock()
+			key = routed
+		})
+		start := m.now()
+		rw := httputil.NewResponseWriter(w)
+		defer func() {
+			m.observe(key, rw, m.now().Sub(start))
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// newRouteKey returns the key of method and route.
+func newRouteKey(method string, route *denco.Route) routeKey {
+	key := routeKey{method: normalizeMethod(method), route: route.Path}
+	if key.route == "" {
+		key.route = UnmatchedRoute
+	}
+	return key
+}
+
+func (m *Metrics) observe(key routeKey, w *httputil.ResponseWriter, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight[key]--
+	m.requests[requestKey{routeKey: key, code: w.StatusCode()}]++
+	if m.durations[key] == nil {
+		m.durations[key] = newHistogram(m.DurationBuckets)
+	}
+	m.durations[key].observe(d.Seconds())
+	if m.sizes[key] == nil {
+		m.sizes[key] = newHistogram(m.SizeBuckets)
+	}
+	m.sizes[key].observe(float64(w.Bytes))
+}
+
+// normalizeMethod returns method if it is a standard method, otherwise "OTHER".
+// It prevents arbitrary methods from increasing the cardinality of metrics.
+func normalizeMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return "OTHER"
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	m.write(bw)
+	bw.Flush()
+}
+
+func (m *Metrics) write(w *bufio.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name := m.name("http_requests_total")
+	fmt.Fprintf(w, "# HELP %s Total number of HTTP requests.\n# TYPE %s counter\n", name, name)
+	reqKeys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		reqKeys = append(reqKeys, k)
+	}
+	sort.Slice(reqKeys, func(i, j int) bool {
+		if reqKeys[i].routeKey != reqKeys[j].routeKey {
+			return reqKeys[i].routeKey.less(reqKeys[j].routeKey)
+		}
+		return reqKeys[i].code < reqKeys[j].code
+	})
+	for _, k := range reqKeys {
+		fmt.Fprintf(w, "%s{%s,code=\"%d\"} %d\n", name, k.labels(), k.code, m.requests[k])
+	}
+
+	name = m.name("http_requests_in_flight")
+	fmt.Fprintf(w, "# HELP %s Number of HTTP requests in flight.\n# TYPE %s gauge\n", name, name)
+	flightKeys := make([]routeKey, 0, len(m.inFlight))
+	for k := range m.inFlight {
+		flightKeys = append(flightKeys, k)
+	}
+	sort.Slice(flightKeys, func(i, j int) bool { return flightKeys[i].less(flightKeys[j]) })
+	for _, k := range flightKeys {
+		fmt.Fprintf(w, "%s{%s} %d\n", name, k.labels(), m.inFlight[k])
+	}
+
+	writeHistograms(w, m.name("http_request_duration_seconds"), "Duration of HTTP requests in seconds.", m.durations)
+	writeHistograms(w, m.name("http_response_size_bytes"), "Size of HTTP responses in bytes.", m.sizes)
+}
+
+func (m *Metrics) name(name string) string {
+	if m.Namespace == "" {
+		return name
+	}
+	return m.Namespace + "_" + name
+}
+
+func (k routeKey) less(o routeKey) bool {
+	if k.route != o.route {
+		return k.route < o.route
+	}
+	return k.method < o.method
+}
+
+func (k routeKey) labels() string {
+	return fmt.Sprintf(`method="%s",route="%s"`, escapeLabel(k.method), escapeLabel(k.route))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, b := range h.bounds {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+func writeHistograms(w *bufio.Writer, name, help string, hists map[routeKey]*histogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	keys := make([]routeKey, 0, len(hists))
+	for k := range hists {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+	for _, k := range keys {
+		h := hists[k]
+		labels := k.labels()
+		for i, b := range h.bounds {
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatFloat(b), h.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels, formatFloat(h.sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, h.count)
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
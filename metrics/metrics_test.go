@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/naoina/denco"
+)
+
+func TestMetrics(t *testing.T) {
+	mux := denco.NewMux()
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/user/:id", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			fmt.Fprintf(w, "user %s", params.Get("id"))
+		}),
+		mux.POST("/user/:id", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			w.WriteHeader(http.StatusCreated)
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New()
+	m.Namespace = "test"
+	m.DurationBuckets = []float64{0.1, 1}
+	m.SizeBuckets = []float64{5, 100}
+	now := time.Date(2014, 1, 6, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time {
+		now = now.Add(250 * time.Millisecond)
+		return now
+	}
+	h := m.Middleware(handler)
+	for _, v := range []struct {
+		method, path string
+	}{
+		{"GET", "/user/1"},
+		{"GET", "/user/22"},
+		{"POST", "/user/1"},
+		{"GET", "/missing"},
+		{"PROPFIND", "/user/1"},
+	} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(v.method, v.path, nil))
+	}
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	expected := strings.Join([]string{
+		`# HELP test_http_requests_total Total number of HTTP requests.`,
+		`# TYPE test_http_requests_total counter`,
+		`test_http_requests_total{method="GET",route="/user/:id",code="200"} 2`,
+		`test_http_requests_total{method="POST",route="/user/:id",code="201"} 1`,
+		`test_http_requests_total{method="GET",route="unmatched",code="404"} 1`,
+		`test_http_requests_total{method="OTHER",route="unmatched",code="404"} 1`,
+		`# HELP test_http_requests_in_flight Number of HTTP requests in flight.`,
+		`# TYPE test_http_requests_in_flight gauge`,
+		`test_http_requests_in_flight{method="GET",route="/user/:id"} 0`,
+		`test_http_requests_in_flight{method="POST",route="/user/:id"} 0`,
+		`test_http_requests_in_flight{method="GET",route="unmatched"} 0`,
+		`test_http_requests_in_flight{method="OTHER",route="unmatched"} 0`,
+		`test_http_requests_in_flight{method="POST",route="unmatched"} 0`,
+		`# HELP test_http_request_duration_seconds Duration of HTTP requests in seconds.`,
+		`# TYPE test_http_request_duration_seconds histogram`,
+		`test_http_request_duration_seconds_bucket{method="GET",route="/user/:id",le="0.1"} 0`,
+		`test_http_request_duration_seconds_bucket{method="GET",route="/user/:id",le="1"} 2`,
+		`test_http_request_duration_seconds_bucket{method="GET",route="/user/:id",le="+Inf"} 2`,
+		`test_http_request_duration_seconds_sum{method="GET",route="/user/:id"} 0.5`,
+		`test_http_request_duration_seconds_count{method="GET",route="/user/:id"} 2`,
+		`test_http_request_duration_seconds_bucket{method="POST",route="/user/:id",le="0.1"} 0`,
+		`test_http_request_duration_seconds_bucket{method="POST",route="/user/:id",le="1"} 1`,
+		`test_http_request_duration_seconds_bucket{method="POST",route="/user/:id",le="+Inf"} 1`,
+		`test_http_request_duration_seconds_sum{method="POST",route="/user/:id"} 0.25`,
+		`test_http_request_duration_seconds_count{method="POST",route="/user/:id"} 1`,
+		`test_http_request_duration_seconds_bucket{method="GET",route="unmatched",le="0.1"} 0`,
+		`test_http_request_duration_seconds_bucket{method="GET",route="unmatched",le="1"} 1`,
+		`test_http_request_duration_seconds_bucket{method="GET",route="unmatched",le="+Inf"} 1`,
+		`test_http_request_duration_seconds_sum{method="GET",route="unmatched"} 0.25`,
+		`test_http_request_duration_seconds_count{method="GET",route="unmatched"} 1`,
+		`test_http_request_duration_seconds_bucket{method="OTHER",route="unmatched",le="0.1"} 0`,
+		`test_http_request_duration_seconds_bucket{method="OTHER",route="unmatched",le="1"} 1`,
+		`test_http_request_duration_seconds_bucket{method="OTHER",route="unmatched",le="+Inf"} 1`,
+		`test_http_request_duration_seconds_sum{method="OTHER",route="unmatched"} 0.25`,
+		`test_http_request_duration_seconds_count{method="OTHER",route="unmatched"} 1`,
+		`# HELP test_http_response_size_bytes Size of HTTP responses in bytes.`,
+		`# TYPE test_http_response_size_bytes histogram`,
+		`test_http_response_size_bytes_bucket{method="GET",route="/user/:id",le="5"} 0`,
+		`test_http_response_size_bytes_bucket{method="GET",route="/user/:id",le="100"} 2`,
+		`test_http_response_size_bytes_bucket{method="GET",route="/user/:id",le="+Inf"} 2`,
+		`test_http_response_size_bytes_sum{method="GET",route="/user/:id"} 13`,
+		`test_http_response_size_bytes_count{method="GET",route="/user/:id"} 2`,
+		`test_http_response_size_bytes_bucket{method="POST",route="/user/:id",le="5"} 1`,
+		`test_http_response_size_bytes_bucket{method="POST",route="/user/:id",le="100"} 1`,
+		`test_http_response_size_bytes_bucket{method="POST",route="/user/:id",le="+Inf"} 1`,
+		`test_http_response_size_bytes_sum{method="POST",route="/user/:id"} 0`,
+		`test_http_response_size_bytes_count{method="POST",route="/user/:id"} 1`,
+		`test_http_response_size_bytes_bucket{method="GET",route="unmatched",le="5"} 0`,
+		`test_http_response_size_bytes_bucket{method="GET",route="unmatched",le="100"} 1`,
+		`test_http_response_size_bytes_bucket{method="GET",route="unmatched",le="+Inf"} 1`,
+		`test_http_response_size_bytes_sum{method="GET",route="unmatched"} 19`,
+		`test_http_response_size_bytes_count{method="GET",route="unmatched"} 1`,
+		`test_http_response_size_bytes_bucket{method="OTHER",route="unmatched",le="5"} 0`,
+		`test_http_response_size_bytes_bucket{method="OTHER",route="unmatched",le="100"} 1`,
+		`test_http_response_size_bytes_bucket{method="OTHER",route="unmatched",le="+Inf"} 1`,
+		`test_http_response_size_bytes_sum{method="OTHER",route="unmatched"} 19`,
+		`test_http_response_size_bytes_count{method="OTHER",route="unmatched"} 1`,
+		``,
+	}, "\n")
+	if actual := w.Body.String(); actual != expected {
+		t.Errorf("Metrics.ServeHTTP => %s, want %s", actual, expected)
+	}
+}
+
+func TestMetrics_inFlight(t *testing.T) {
+	m := New()
+	var inFlight map[routeKey]int64
+	mux := denco.NewMux()
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/user/:id", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			m.mu.Lock()
+			inFlight = make(map[routeKey]int64)
+			for k, v := range m.inFlight {
+				inFlight[k] = v
+			}
+			m.mu.Unlock()
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Middleware(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/1", nil))
+	key := routeKey{method: "GET", route: "/user/:id"}
+	unmatched := routeKey{method: "GET", route: UnmatchedRoute}
+	if inFlight[key] != 1 || inFlight[unmatched] != 0 {
+		t.Errorf("in flight during request => %v, want 1 for %v", inFlight, key)
+	}
+	if m.inFlight[key] != 0 || m.inFlight[unmatched] != 0 {
+		t.Errorf("in flight after request => %v, want 0", m.inFlight)
+	}
+}
+
+func TestMetrics_timeoutMount(t *testing.T) {
+	sub := denco.NewMux()
+	subHandler, err := sub.Build([]denco.Handler{
+		sub.GET("/users/:id", func(w http.ResponseWriter, r *http.Request, params denco.Params) {}),
+		sub.GET("/slow", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			<-r.Context().Done()
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := denco.NewMux()
+	mux.Timeout = 10 * time.Millisecond
+	handler, err := mux.Build(mux.Mount("/admin", subHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New()
+	h := m.Middleware(handler)
+	for _, path := range []string{"/admin/users/1", "/admin/slow"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, expected := range map[requestKey]uint64{
+		{routeKey{method: "GET", route: "/admin/users/:id"}, http.StatusOK}:             1,
+		{routeKey{method: "GET", route: "/admin/*rest"}, http.StatusServiceUnavailable}: 1,
+	} {
+		if actual := m.requests[key]; actual != expected {
+			t.Errorf("requests[%v] => %v, want %v; %v", key, actual, expected, m.requests)
+		}
+	}
+	for key, n := range m.inFlight {
+		if n != 0 {
+			t.Errorf("inFlight[%v] => %v, want 0", key, n)
+		}
+	}
+}
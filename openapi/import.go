@@ -0,0 +1,141 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/naoina/denco"
+)
+
+// Parse parses an OpenAPI 3 document in JSON.
+func Parse(r io.Reader) (*Document, error) {
+	var doc Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("openapi: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("openapi: unsupported version `%v'", doc.OpenAPI)
+	}
+	if err := checkPaths(&doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// checkPaths returns an error if doc has a path that has no Path Item Object.
+func checkPaths(doc *Document) error {
+	for tmpl, item := range doc.Paths {
+		if item == nil {
+			return fmt.Errorf("openapi: path `%v' has no path item", tmpl)
+		}
+	}
+	return nil
+}
+
+// FromTemplate converts a path template of OpenAPI to a routing path of denco.
+// e.g. "/users/{id}" will be converted to "/users/:id".
+// FromTemplate returns an error if tmpl cannot be represented by denco,
+// such as a template parameter that doesn't occupy the whole path segment.
+func FromTemplate(tmpl string) (string, error) {
+	var buf bytes.Buffer
+	for i := 0; i < len(tmpl); i++ {
+		switch c := tmpl[i]; c {
+		case '{':
+			end := strings.IndexByte(tmpl[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("openapi: unclosed template parameter in `%v'", tmpl)
+			}
+			end += i
+			if i > 0 && tmpl[i-1] != '/' || denco.NextSeparator(tmpl, end+1) != end+1 {
+				return "", fmt.Errorf("openapi: template parameter must be a whole path segment in `%v'", tmpl)
+			}
+			name := tmpl[i+1 : end]
+			if name == "" || strings.ContainsAny(name, "{/") {
+				return "", fmt.Errorf("openapi: invalid template parameter name `%v' in `%v'", name, tmpl)
+			}
+			buf.WriteByte(denco.ParamCharacter)
+			buf.WriteString(name)
+			i = end
+		case '}', denco.ParamCharacter, denco.WildcardCharacter, denco.TerminationCharacter:
+			return "", fmt.Errorf("openapi: unexpected character `%c' in `%v'", c, tmpl)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String(), nil
+}
+
+// Unhandled represents an operation that has no handler function.
+type Unhandled struct {
+	Method      string
+	Path        string
+	OperationID string
+}
+
+// Import returns handlers for Mux.Build from the operations of doc.
+// Each operation is paired with the handler function in funcs by operationId.
+// Operations that have no operationId or no handler function are returned as unhandled.
+// Import returns an error if a path has no Path Item Object, a path template cannot
+// be represented by denco or the routes cannot be built by denco.Router.
+func Import(mux *denco.Mux, doc *Document, funcs map[string]denco.HandlerFunc) (handlers []denco.Handler, unhandled []Unhandled, err error) {
+	if err := checkPaths(doc); err != nil {
+		return nil, nil, err
+	}
+	tmpls := make([]string, 0, len(doc.Paths))
+	for tmpl := range doc.Paths {
+		tmpls = append(tmpls, tmpl)
+	}
+	sort.Strings(tmpls)
+	records := make(map[string][]denco.Record)
+	shapes := make(map[string]string)
+	for _, tmpl := range tmpls {
+		path, err := FromTemplate(tmpl)
+		if err != nil {
+			return nil, nil, err
+		}
+		ops := doc.Paths[tmpl].Operations()
+		methods := make([]string, 0, len(ops))
+		for method := range ops {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			shape := method + " " + pathShape(path)
+			if dup, found := shapes[shape]; found {
+				return nil, nil, fmt.Errorf("openapi: `%v %v' is ambiguous with `%v %v'", method, tmpl, method, dup)
+			}
+			shapes[shape] = tmpl
+			records[method] = append(records[method], denco.NewRecord(path, nil))
+			op := ops[method]
+			fn, found := funcs[op.OperationID]
+			if op.OperationID == "" || !found {
+				unhandled = append(unhandled, Unhandled{Method: method, Path: tmpl, OperationID: op.OperationID})
+				continue
+			}
+			handlers = append(handlers, mux.Handler(method, path, fn))
+		}
+	}
+	for _, rs := range records {
+		if err := denco.New().Build(rs); err != nil {
+			return nil, nil, err
+		}
+	}
+	return handlers, unhandled, nil
+}
+
+// pathShape returns path that the names of path parameters are removed.
+// Paths that have the same shape cannot be distinguished by denco.
+func pathShape(path string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(path); i++ {
+		buf.WriteByte(path[i])
+		if path[i] == denco.ParamCharacter {
+			i = denco.NextSeparator(path, i+1) - 1
+		}
+	}
+	return buf.String()
+}
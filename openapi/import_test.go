@@ -0,0 +1,141 @@
+package openapi_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/openapi"
+)
+
+func TestFromTemplate(t *testing.T) {
+	for _, v := range []struct {
+		tmpl     string
+		expected string
+	}{
+		{"/", "/"},
+		{"/users/{id}", "/users/:id"},
+		{"/users/{id}/files/{name}", "/users/:id/files/:name"},
+	} {
+		actual, err := openapi.FromTemplate(v.tmpl)
+		if err != nil {
+			t.Errorf("FromTemplate(%q) => %v", v.tmpl, err)
+			continue
+		}
+		if actual != v.expected {
+			t.Errorf("FromTemplate(%q) => %q, want %q", v.tmpl, actual, v.expected)
+		}
+	}
+	for _, tmpl := range []string{
+		"/users/{id",
+		"/users/{id}.json",
+		"/users/v{id}",
+		"/users/{}",
+		"/users/{a}{b}",
+		"/users/:id",
+		"/users/*",
+		"/users/#",
+		"/users/id}",
+	} {
+		if actual, err := openapi.FromTemplate(tmpl); err == nil {
+			t.Errorf("FromTemplate(%q) => %q, want error", tmpl, actual)
+		}
+	}
+}
+
+const testDocument = `{
+  "openapi": "3.0.0",
+  "info": {"title": "test", "version": "1.0"},
+  "paths": {
+    "/users/{id}": {
+      "get": {"operationId": "getUser", "responses": {"200": {"description": "OK"}}},
+      "delete": {"operationId": "deleteUser", "responses": {"204": {"description": "No Content"}}}
+    },
+    "/users": {
+      "post": {"operationId": "createUser", "responses": {"200": {"description": "OK"}}},
+      "get": {"responses": {"200": {"description": "OK"}}}
+    }
+  }
+}`
+
+func TestImport(t *testing.T) {
+	doc, err := openapi.Parse(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := denco.NewMux()
+	handlers, unhandled, err := openapi.Import(mux, doc, map[string]denco.HandlerFunc{
+		"getUser": func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			fmt.Fprintf(w, "user %s", params.Get("id"))
+		},
+		"createUser": func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			fmt.Fprintf(w, "created")
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []openapi.Unhandled{
+		{Method: "GET", Path: "/users"},
+		{Method: "DELETE", Path: "/users/{id}", OperationID: "deleteUser"},
+	}
+	if !reflect.DeepEqual(unhandled, expected) {
+		t.Errorf("Import(...) unhandled => %#v, want %#v", unhandled, expected)
+	}
+	handler, err := mux.Build(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{"GET", "/users/alice", 200, "user alice"},
+		{"POST", "/users", 200, "created"},
+		{"GET", "/users", 404, "404 page not found\n"},
+		{"DELETE", "/users/alice", 404, "404 page not found\n"},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(v.method, v.path, nil))
+		if w.Code != v.status || w.Body.String() != v.body {
+			t.Errorf(`%s "%s" => %#v %#v, want %#v %#v`, v.method, v.path, w.Code, w.Body.String(), v.status, v.body)
+		}
+	}
+}
+
+func TestImport_withInvalidDocument(t *testing.T) {
+	for _, paths := range []string{
+		`{"/users/{id}.json": {"get": {"responses": {}}}}`,
+		`{"/users/{id}": {"get": {"responses": {}}}, "/users/{name}": {"get": {"responses": {}}}}`,
+		`{"/users/{id}/{id}": {"get": {"responses": {}}}}`,
+	} {
+		doc, err := openapi.Parse(strings.NewReader(`{"openapi": "3.0.0", "paths": ` + paths + `}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := openapi.Import(denco.NewMux(), doc, nil); err == nil {
+			t.Errorf("Import(%v) => nil, want error", paths)
+		}
+	}
+	doc := &openapi.Document{OpenAPI: "3.0.0", Paths: map[string]*openapi.PathItem{"/x": nil}}
+	if _, _, err := openapi.Import(denco.NewMux(), doc, nil); err == nil {
+		t.Errorf("Import with nil PathItem => nil, want error")
+	}
+}
+
+func TestParse_withUnsupportedVersion(t *testing.T) {
+	if _, err := openapi.Parse(strings.NewReader(`{"swagger": "2.0", "paths": {}}`)); err == nil {
+		t.Errorf("Parse with Swagger 2.0 => nil, want error")
+	}
+}
+
+func TestParse_withNullPathItem(t *testing.T) {
+	if _, err := openapi.Parse(strings.NewReader(`{"openapi": "3.0.0", "paths": {"/x": null}}`)); err == nil {
+		t.Errorf("Parse with null path item => nil, want error")
+	}
+}
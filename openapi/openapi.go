@@ -0,0 +1,354 @@
+// Package openapi generates OpenAPI 3 documents from the routes of denco.Mux.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/naoina/denco"
+)
+
+// Version is a version of OpenAPI Specification of generated documents.
+const Version = "3.0.3"
+
+// Document represents an OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components,omitempty"`
+}
+
+// Info represents an Info Object.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server represents a Server Object.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components represents a Components Object.
+// Only schemas are supported.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// PathItem represents a Path Item Object.
+type PathItem struct {
+	Get        *Operation  `json:"get,omitempty"`
+	Put        *Operation  `json:"put,omitempty"`
+	Post       *Operation  `json:"post,omitempty"`
+	Delete     *Operation  `json:"delete,omitempty"`
+	Options    *Operation  `json:"options,omitempty"`
+	Head       *Operation  `json:"head,omitempty"`
+	Patch      *Operation  `json:"patch,omitempty"`
+	Trace      *Operation  `json:"trace,omitempty"`
+	Parameters []Parameter `json:"parameters,omitempty"`
+}
+
+// Operations returns operations of the PathItem with HTTP methods.
+func (p *PathItem) Operations() map[string]*Operation {
+	ops := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		http.MethodGet:     p.Get,
+		http.MethodPut:     p.Put,
+		http.MethodPost:    p.Post,
+		http.MethodDelete:  p.Delete,
+		http.MethodOptions: p.Options,
+		http.MethodHead:    p.Head,
+		http.MethodPatch:   p.Patch,
+		http.MethodTrace:   p.Trace,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+// operation returns a pointer to the field of the operation for method.
+// operation returns nil if method isn't supported by OpenAPI.
+func (p *PathItem) operation(method string) **Operation {
+	switch method {
+	case http.MethodGet:
+		return &p.Get
+	case http.MethodPut:
+		return &p.Put
+	case http.MethodPost:
+		return &p.Post
+	case http.MethodDelete:
+		return &p.Delete
+	case http.MethodOptions:
+		return &p.Options
+	case http.MethodHead:
+		return &p.Head
+	case http.MethodPatch:
+		return &p.Patch
+	case http.MethodTrace:
+		return &p.Trace
+	}
+	return nil
+}
+
+// Operation represents an Operation Object.
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+}
+
+// Parameter represents a Parameter Object.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody represents a Request Body Object.
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+	Required    bool                  `json:"required,omitempty"`
+}
+
+// Response represents a Response Object.
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType represents a Media Type Object.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Meta represents metadata of a route for generating an Operation Object.
+type Meta struct {
+	// OperationID is a unique identifier of the operation.
+	OperationID string
+
+	// Summary is a short summary of the operation.
+	Summary string
+
+	// Description is a verbose explanation of the operation.
+	Description string
+
+	// Tags is a list of tags for grouping operations.
+	Tags []string
+
+	// Deprecated declares the operation to be deprecated.
+	Deprecated bool
+
+	// Request is a value of the type of the request body, e.g. User{} or (*User)(nil).
+	// If nil, the operation has no request body.
+	Request interface{}
+
+	// Responses is a map of status code and response.
+	// If empty, a response of 200 without content will be used.
+	Responses map[int]ResponseMeta
+
+	// ContentType is a media type of request and response bodies.
+	// If empty, "application/json" will be used.
+	ContentType string
+}
+
+// ResponseMeta represents metadata of a response.
+type ResponseMeta struct {
+	// Description of the response.
+	// If empty, the status text of the status code will be used.
+	Description string
+
+	// Body is a value of the type of the response body.
+	// If nil, the response has no content.
+	Body interface{}
+}
+
+// Generator generates OpenAPI documents from handlers of denco.Mux.
+type Generator struct {
+	// Info is the metadata about the API.
+	Info Info
+
+	// Servers is a list of servers that provide the API.
+	Servers []Server
+}
+
+// MetaKey is the key of denco.Meta that Describe associates Meta with.
+const MetaKey = "openapi"
+
+// NewGenerator returns a new Generator.
+func NewGenerator(info Info) *Generator {
+	return &Generator{
+		Info: info,
+	}
+}
+
+// Describe returns a copy of the handler that meta is associated with by denco.Handler.WithMeta.
+// It is intended to wrap the handler to be passed to Mux.Build.
+//
+//	mux.Build([]denco.Handler{
+//	    gen.Describe(mux.GET("/user/:id", GetUser), openapi.Meta{Summary: "Get a user"}),
+//	})
+func (g *Generator) Describe(h denco.Handler, meta Meta) denco.Handler {
+	return h.WithMeta(MetaKey, meta)
+}
+
+// Generate generates an OpenAPI document from handlers.
+// Handlers that have no method are ignored.
+// OpenAPI cannot describe handlers that share the same method and path but differ in
+// Host or Matchers, so only one of them is described: the first one that is described
+// by Describe, or the first one if none of them is described.
+func (g *Generator) Generate(handlers []denco.Handler) (*Document, error) {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    g.Info,
+		Servers: g.Servers,
+		Paths:   make(map[string]*PathItem),
+	}
+	schemas := newSchemaGenerator()
+	described := make(map[*Operation]bool)
+	for _, h := range handlers {
+		if h.Method == "" {
+			// Handlers for any method such as returned by Mux.Mount cannot be described.
+			continue
+		}
+		tmpl, names, err := ToTemplate(h.Path)
+		if err != nil {
+			return nil, err
+		}
+		item := doc.Paths[tmpl]
+		if item == nil {
+			item = &PathItem{}
+			doc.Paths[tmpl] = item
+		}
+		field := item.operation(h.Method)
+		if field == nil {
+			return nil, fmt.Errorf("openapi: method `%v' of `%v' is not supported by OpenAPI", h.Method, h.Path)
+		}
+		_, hasMeta := h.Meta[MetaKey].(Meta)
+		if *field != nil && (described[*field] || !hasMeta) {
+			continue
+		}
+		*field = g.operation(h, names, schemas)
+		described[*field] = hasMeta
+	}
+	if len(schemas.components) > 0 {
+		doc.Components = &Components{Schemas: schemas.components}
+	}
+	return doc, nil
+}
+
+func (g *Generator) operation(h denco.Handler, names []string, schemas *schemaGenerator) *Operation {
+	meta, _ := h.Meta[MetaKey].(Meta)
+	contentType := meta.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	op := &Operation{
+		Tags:        meta.Tags,
+		Summary:     meta.Summary,
+		Description: meta.Description,
+		OperationID: meta.OperationID,
+		Deprecated:  meta.Deprecated,
+		Responses:   make(map[string]*Response),
+	}
+	for _, name := range names {
+		param := Parameter{
+			Name:     strings.TrimPrefix(name, string(denco.WildcardCharacter)),
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		}
+		if name[0] == denco.WildcardCharacter {
+			param.Description = "The rest of the path, may contain `/`."
+		}
+		op.Parameters = append(op.Parameters, param)
+	}
+	if meta.Request != nil {
+		op.RequestBody = &RequestBody{
+			Content: map[string]*MediaType{
+				contentType: {Schema: schemas.schema(meta.Request)},
+			},
+			Required: true,
+		}
+	}
+	if len(meta.Responses) == 0 {
+		op.Responses[strconv.Itoa(http.StatusOK)] = &Response{Description: http.StatusText(http.StatusOK)}
+	}
+	for code, res := range meta.Responses {
+		r := &Response{Description: res.Description}
+		if r.Description == "" {
+			r.Description = http.StatusText(code)
+		}
+		if res.Body != nil {
+			r.Content = map[string]*MediaType{
+				contentType: {Schema: schemas.schema(res.Body)},
+			}
+		}
+		op.Responses[strconv.Itoa(code)] = r
+	}
+	return op
+}
+
+// ToTemplate converts a routing path of denco to a path template of OpenAPI.
+// names are the names of path parameters in the order in which they appeared.
+// The name of a wildcard parameter is prefixed with denco.WildcardCharacter.
+// e.g. "/users/:id/files/*path" will be converted to "/users/{id}/files/{path}" and ["id", "*path"].
+// ToTemplate returns an error if path has a path parameter without name such as "/users/:".
+func ToTemplate(path string) (tmpl string, names []string, err error) {
+	var buf bytes.Buffer
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case denco.ParamCharacter:
+			next := denco.NextSeparator(path, i+1)
+			name := path[i+1 : next]
+			if name == "" {
+				return "", nil, fmt.Errorf("openapi: path parameter must have a name in `%v'", path)
+			}
+			names = append(names, name)
+			fmt.Fprintf(&buf, "{%s}", name)
+			i = next - 1
+		case denco.WildcardCharacter:
+			name := path[i+1:]
+			if name == "" {
+				return "", nil, fmt.Errorf("openapi: wildcard parameter must have a name in `%v'", path)
+			}
+			names = append(names, string(c)+name)
+			fmt.Fprintf(&buf, "{%s}", name)
+			i = len(path)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String(), names, nil
+}
+
+// JSON returns the document as indented JSON.
+func (doc *Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// YAML returns the document as YAML.
+func (doc *Document) YAML() ([]byte, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
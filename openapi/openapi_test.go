@@ -0,0 +1,215 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/openapi"
+)
+
+type user struct {
+	ID      int64    `json:"id"`
+	Name    string   `json:"name"`
+	Email   *string  `json:"email,omitempty"`
+	Friends []*user  `json:"friends,omitempty"`
+	Tags    []string `json:"-"`
+}
+
+func testHandlerFunc(w http.ResponseWriter, r *http.Request, params denco.Params) {}
+
+func TestToTemplate(t *testing.T) {
+	for _, v := range []struct {
+		path  string
+		tmpl  string
+		names []string
+	}{
+		{"/", "/", nil},
+		{"/users/:id", "/users/{id}", []string{"id"}},
+		{"/users/:id/files/:name/raw", "/users/{id}/files/{name}/raw", []string{"id", "name"}},
+		{"/static/*filepath", "/static/{filepath}", []string{"*filepath"}},
+	} {
+		tmpl, names, err := openapi.ToTemplate(v.path)
+		if err != nil {
+			t.Errorf("ToTemplate(%q) => %v", v.path, err)
+			continue
+		}
+		if tmpl != v.tmpl || !reflect.DeepEqual(names, v.names) {
+			t.Errorf("ToTemplate(%q) => (%q, %#v), want (%q, %#v)", v.path, tmpl, names, v.tmpl, v.names)
+		}
+	}
+	for _, path := range []string{"/users/:", "/users/:/raw", "/files/*"} {
+		if _, _, err := openapi.ToTemplate(path); err == nil {
+			t.Errorf("ToTemplate(%q) => nil, want error", path)
+		}
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	mux := denco.NewMux()
+	gen := openapi.NewGenerator(openapi.Info{Title: "test", Version: "1.0"})
+	handlers := []denco.Handler{
+		gen.Describe(mux.GET("/users/:id", testHandlerFunc), openapi.Meta{
+			OperationID: "getUser",
+			Summary:     "Get a user",
+			Tags:        []string{"users"},
+			Responses: map[int]openapi.ResponseMeta{
+				200: {Body: user{}},
+				404: {Description: "No such user"},
+			},
+		}),
+		gen.Describe(mux.POST("/users", testHandlerFunc), openapi.Meta{
+			OperationID: "createUser",
+			Request:     (*user)(nil),
+		}),
+		mux.GET("/static/*filepath", testHandlerFunc),
+	}
+	doc, err := gen.Generate(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := doc.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual interface{}
+	if err := json.Unmarshal(b, &actual); err != nil {
+		t.Fatal(err)
+	}
+	var expected interface{}
+	if err := json.Unmarshal([]byte(`{
+  "openapi": "3.0.3",
+  "info": {"title": "test", "version": "1.0"},
+  "paths": {
+    "/users/{id}": {
+      "get": {
+        "tags": ["users"],
+        "summary": "Get a user",
+        "operationId": "getUser",
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/user"}}}},
+          "404": {"description": "No such user"}
+        }
+      }
+    },
+    "/users": {
+      "post": {
+        "operationId": "createUser",
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/user"}}}, "required": true},
+        "responses": {"200": {"description": "OK"}}
+      }
+    },
+    "/static/{filepath}": {
+      "get": {
+        "parameters": [{"name": "filepath", "in": "path", "description": "The rest of the path, may contain `+"`/`"+`.", "required": true, "schema": {"type": "string"}}],
+        "responses": {"200": {"description": "OK"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "user": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "integer", "format": "int64"},
+          "name": {"type": "string"},
+          "email": {"type": "string", "nullable": true},
+          "friends": {"type": "array", "items": {"$ref": "#/components/schemas/user"}}
+        },
+        "required": ["id", "name"]
+      }
+    }
+  }
+}`), &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Generator.Generate(%#v) => %s, want %s", handlers, b, expected)
+	}
+}
+
+func TestGenerator_Generate_withInvalidHandlers(t *testing.T) {
+	mux := denco.NewMux()
+	gen := openapi.NewGenerator(openapi.Info{Title: "test", Version: "1.0"})
+	if _, err := gen.Generate([]denco.Handler{mux.Handler("PROPFIND", "/", testHandlerFunc)}); err == nil {
+		t.Errorf("Generator.Generate with unsupported method => nil, want error")
+	}
+	if _, err := gen.Generate([]denco.Handler{mux.GET("/a/:", testHandlerFunc)}); err == nil {
+		t.Errorf("Generator.Generate with unnamed path parameter => nil, want error")
+	}
+}
+
+func TestGenerator_Generate_withDuplicatedOperations(t *testing.T) {
+	mux := denco.NewMux()
+	gen := openapi.NewGenerator(openapi.Info{Title: "test", Version: "1.0"})
+	handlers := []denco.Handler{
+		mux.GET("/v", testHandlerFunc).Match(denco.MatchHeader("Accept", "application/vnd.v2+json")),
+		gen.Describe(mux.GET("/v", testHandlerFunc), openapi.Meta{Summary: "fallback"}),
+		mux.GET("/v", testHandlerFunc).Match(denco.MatchQuery("debug")),
+	}
+	handlers = append(handlers, mux.Host("api.example.com",
+		gen.Describe(mux.GET("/v", testHandlerFunc), openapi.Meta{Summary: "host"}),
+		mux.GET("/w", testHandlerFunc),
+	)...)
+	handlers = append(handlers, mux.GET("/w", testHandlerFunc))
+	if _, err := mux.Build(handlers); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := gen.Generate(handlers)
+	if err != nil {
+		t.Fatalf("Generator.Generate => %v, want nil", err)
+	}
+	if actual, expected := doc.Paths["/v"].Get.Summary, "fallback"; actual != expected {
+		t.Errorf("summary of GET /v => %q, want %q", actual, expected)
+	}
+	if op := doc.Paths["/w"].Get; op == nil {
+		t.Errorf("GET /w => nil, want an operation")
+	}
+}
+
+func TestDocument_YAML(t *testing.T) {
+	mux := denco.NewMux()
+	gen := openapi.NewGenerator(openapi.Info{Title: "test", Version: "1.0"})
+	doc, err := gen.Generate([]denco.Handler{
+		gen.Describe(mux.GET("/users/:id", testHandlerFunc), openapi.Meta{
+			Tags: []string{"users", "true"},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := doc.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := string(b)
+	expected := strings.Join([]string{
+		`openapi: "3.0.3"`,
+		`info:`,
+		`  title: test`,
+		`  version: "1.0"`,
+		`paths:`,
+		`  /users/{id}:`,
+		`    get:`,
+		`      tags:`,
+		`        - users`,
+		`        - "true"`,
+		`      parameters:`,
+		`        - name: id`,
+		`          in: path`,
+		`          required: true`,
+		`          schema:`,
+		`            type: string`,
+		`      responses:`,
+		`        "200":`,
+		`          description: OK`,
+		``,
+	}, "\n")
+	if actual != expected {
+		t.Errorf("Document.YAML() => %q, want %q", actual, expected)
+	}
+}
@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// jsonToYAML converts JSON to block style YAML.
+// The order of keys of objects is preserved.
+func jsonToYAML(b []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	node, err := decodeYAMLNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	node.write(&buf, 0)
+	return buf.Bytes(), nil
+}
+
+// yamlNode represents a JSON value that keeps the order of keys.
+type yamlNode struct {
+	scalar string
+	keys   []string
+	values []*yamlNode
+	object bool
+	array  bool
+}
+
+func decodeYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		n := &yamlNode{object: t == '{', array: t == '['}
+		for dec.More() {
+			if n.object {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, key.(string))
+			}
+			v, err := decodeYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.values = append(n.values, v)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case string:
+		return &yamlNode{scalar: yamlString(t)}, nil
+	case nil:
+		return &yamlNode{scalar: "null"}, nil
+	default:
+		return &yamlNode{scalar: fmt.Sprint(t)}, nil
+	}
+}
+
+func (n *yamlNode) isCollection() bool {
+	return (n.object || n.array) && len(n.values) > 0
+}
+
+// write writes n at the indentation of depth.
+// The cursor of buf must be at the indentation of depth already.
+func (n *yamlNode) write(buf *bytes.Buffer, depth int) {
+	indent := strings.Repeat("  ", depth)
+	switch {
+	case n.object && len(n.values) == 0:
+		buf.WriteString("{}\n")
+	case n.array && len(n.values) == 0:
+		buf.WriteString("[]\n")
+	case n.object:
+		for i, v := range n.values {
+			if i > 0 {
+				buf.WriteString(indent)
+			}
+			buf.WriteString(yamlString(n.keys[i]))
+			if v.isCollection() {
+				buf.WriteString(":\n" + indent + "  ")
+			} else {
+				buf.WriteString(": ")
+			}
+			v.write(buf, depth+1)
+		}
+	case n.array:
+		for i, v := range n.values {
+			if i > 0 {
+				buf.WriteString(indent)
+			}
+			buf.WriteString("- ")
+			v.write(buf, depth+1)
+		}
+	default:
+		buf.WriteString(n.scalar)
+		buf.WriteString("\n")
+	}
+}
+
+var yamlPlainRegexp = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_./{}$#-]*$`)
+
+// yamlString returns s as a YAML scalar.
+// s will be quoted if it cannot be represented as a plain scalar.
+func yamlString(s string) string {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "y", "n", "~":
+		return quoteYAML(s)
+	}
+	if yamlPlainRegexp.MatchString(s) {
+		return s
+	}
+	return quoteYAML(s)
+}
+
+// quoteYAML returns s as a double-quoted scalar.
+// A JSON string is also a valid double-quoted scalar of YAML.
+func quoteYAML(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,203 @@
+package denco
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/naoina/denco/internal/bind"
+)
+
+// ErrParamNotFound is returned when the path parameter is not found.
+var ErrParamNotFound = errors.New("not found")
+
+// ParamError represents an error of conversion of the path parameter.
+type ParamError struct {
+	// Name is a name of the path parameter.
+	Name string
+
+	// Value is a value of the path parameter.
+	Value string
+
+	Err error
+}
+
+func (e *ParamError) Error() string {
+	if e.Err == ErrParamNotFound {
+		return fmt.Sprintf("path parameter `%v' is not found", e.Name)
+	}
+	return fmt.Sprintf("invalid path parameter `%v' %q: %v", e.Name, e.Value, e.Err)
+}
+
+// StatusCode returns http.StatusBadRequest.
+func (e *ParamError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// ParamErrors is a list of errors of the path parameters that returned by Params.Bind.
+type ParamErrors []*ParamError
+
+func (errs ParamErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// StatusCode returns http.StatusBadRequest.
+func (errs ParamErrors) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// Has reports whether the path parameter name exists.
+func (ps Params) Has(name string) bool {
+	for _, p := range ps {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the first value associated with the given name.
+func (ps Params) lookup(name string) (string, error) {
+	for _, p := range ps {
+		if p.Name == name {
+			return p.Value, nil
+		}
+	}
+	return "", &ParamError{Name: name, Err: ErrParamNotFound}
+}
+
+// Int returns the value of the path parameter name as int.
+func (ps Params) Int(name string) (int, error) {
+	v, err := ps.Int64(name)
+	if err != nil {
+		return 0, err
+	}
+	if int64(int(v)) != v {
+		return 0, &ParamError{Name: name, Value: ps.Get(name), Err: strconv.ErrRange}
+	}
+	return int(v), nil
+}
+
+// Int64 returns the value of the path parameter name as int64.
+func (ps Params) Int64(name string) (int64, error) {
+	s, err := ps.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, &ParamError{Name: name, Value: s, Err: err.(*strconv.NumError).Err}
+	}
+	return v, nil
+}
+
+// Uint returns the value of the path parameter name as uint.
+func (ps Params) Uint(name string) (uint, error) {
+	s, err := ps.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseUint(s, 10, strconv.IntSize)
+	if err != nil {
+		return 0, &ParamError{Name: name, Value: s, Err: err.(*strconv.NumError).Err}
+	}
+	return uint(v), nil
+}
+
+// Bool returns the value of the path parameter name as bool.
+// It accepts the values that accepted by strconv.ParseBool.
+func (ps Params) Bool(name string) (bool, error) {
+	s, err := ps.lookup(name)
+	if err != nil {
+		return false, err
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, &ParamError{Name: name, Value: s, Err: err.(*strconv.NumError).Err}
+	}
+	return v, nil
+}
+
+// UUID returns the value of the path parameter name as UUID in the canonical form.
+// The value must be in the form of "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" in hexadecimal digits,
+// and the returned value is lowercased.
+func (ps Params) UUID(name string) (string, error) {
+	s, err := ps.lookup(name)
+	if err != nil {
+		return "", err
+	}
+	if !isUUID(s) {
+		return "", &ParamError{Name: name, Value: s, Err: errors.New("invalid UUID")}
+	}
+	return strings.ToLower(s), nil
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if c != '-' {
+				return false
+			}
+		case '0' <= c && c <= '9', 'a' <= c && c <= 'f', 'A' <= c && c <= 'F':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Bind stores the path parameters into the fields of the struct that v points to.
+// The name of the path parameter is specified by `path' tag of the field, e.g.
+//
+//	var p struct {
+//		ID   int64  `path:"id,required"`
+//		Name string `path:"name"`
+//	}
+//	err := params.Bind(&p)
+//
+// The fields must be of bool, string, integer, floating-point or a type that
+// implements encoding.TextUnmarshaler. The field is left as is if the path
+// parameter isn't found, unless the tag has "required" option.
+// The tag is the same as the binding package.
+// If there are errors, Bind returns ParamErrors that contains all of them.
+func (ps Params) Bind(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("denco: Bind requires a pointer to struct, but %T given", v)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	var errs ParamErrors
+	for i := 0; i < rt.NumField(); i++ {
+		tag, found := bind.LookupTag(rt.Field(i), bind.PathTag)
+		if !found {
+			continue
+		}
+		name := tag.Name
+		s, err := ps.lookup(name)
+		if err != nil {
+			if tag.Required {
+				errs = append(errs, err.(*ParamError))
+			}
+			continue
+		}
+		if err := bind.SetValue(rv.Field(i), s); err != nil {
+			errs = append(errs, &ParamError{Name: name, Value: s, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package denco_test
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/naoina/denco"
+)
+
+func TestParams_accessors(t *testing.T) {
+	params := denco.Params{
+		{Name: "id", Value: "42"},
+		{Name: "neg", Value: "-7"},
+		{Name: "big", Value: "99999999999999999999"},
+		{Name: "flag", Value: "true"},
+		{Name: "name", Value: "alice"},
+		{Name: "uuid", Value: "F47AC10B-58CC-4372-A567-0E02B2C3D479"},
+	}
+	for _, v := range []struct {
+		name     string
+		call     func() (interface{}, error)
+		expected interface{}
+		err      error
+	}{
+		{"Int(id)", func() (interface{}, error) { return params.Int("id") }, 42, nil},
+		{"Int(neg)", func() (interface{}, error) { return params.Int("neg") }, -7, nil},
+		{"Int(name)", func() (interface{}, error) { return params.Int("name") }, 0, strconv.ErrSyntax},
+		{"Int(missing)", func() (interface{}, error) { return params.Int("missing") }, 0, denco.ErrParamNotFound},
+		{"Int64(big)", func() (interface{}, error) { return params.Int64("big") }, int64(0), strconv.ErrRange},
+		{"Uint(id)", func() (interface{}, error) { return params.Uint("id") }, uint(42), nil},
+		{"Uint(neg)", func() (interface{}, error) { return params.Uint("neg") }, uint(0), strconv.ErrSyntax},
+		{"Bool(flag)", func() (interface{}, error) { return params.Bool("flag") }, true, nil},
+		{"Bool(name)", func() (interface{}, error) { return params.Bool("name") }, false, strconv.ErrSyntax},
+		{"UUID(uuid)", func() (interface{}, error) { return params.UUID("uuid") }, "f47ac10b-58cc-4372-a567-0e02b2c3d479", nil},
+	} {
+		actual, err := v.call()
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("Params.%s => %#v, want %#v", v.name, actual, v.expected)
+		}
+		if v.err == nil {
+			if err != nil {
+				t.Errorf("Params.%s => error %v, want nil", v.name, err)
+			}
+			continue
+		}
+		if e, ok := err.(*denco.ParamError); !ok || e.Err != v.err {
+			t.Errorf("Params.%s => error %#v, want %v", v.name, err, v.err)
+		}
+	}
+	if _, err := params.UUID("name"); err == nil {
+		t.Errorf("Params.UUID(%q) => nil, want error", "name")
+	}
+	if !params.Has("name") || params.Has("missing") {
+		t.Errorf("Params.Has => %v, %v, want true, false", params.Has("name"), params.Has("missing"))
+	}
+}
+
+func TestParams_Bind(t *testing.T) {
+	type target struct {
+		ID      int64   `path:"id,required,omitempty"`
+		Name    string  `path:"name"`
+		Page    *uint16 `path:"page"`
+		Ratio   float64 `path:"ratio"`
+		IP      net.IP  `path:"ip"`
+		Missing string  `path:"missing"`
+		Ignored string
+	}
+	page := uint16(3)
+	var actual target
+	if err := (denco.Params{
+		{Name: "id", Value: "1"},
+		{Name: "name", Value: "alice"},
+		{Name: "page", Value: "3"},
+		{Name: "ratio", Value: "0.5"},
+		{Name: "ip", Value: "127.0.0.1"},
+		{Name: "Ignored", Value: "x"},
+	}).Bind(&actual); err != nil {
+		t.Fatal(err)
+	}
+	expected := target{ID: 1, Name: "alice", Page: &page, Ratio: 0.5, IP: net.ParseIP("127.0.0.1")}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Params.Bind => %#v, want %#v", actual, expected)
+	}
+
+	err := (denco.Params{
+		{Name: "page", Value: "70000"},
+		{Name: "ip", Value: "localhost"},
+	}).Bind(&actual)
+	errs, ok := err.(denco.ParamErrors)
+	if !ok {
+		t.Fatalf("Params.Bind => %#v, want denco.ParamErrors", err)
+	}
+	var names []string
+	for _, e := range errs {
+		names = append(names, e.Name)
+	}
+	if expected := []string{"id", "page", "ip"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Params.Bind => errors of %v, want %v; %v", names, expected, err)
+	}
+	if actual, expected := errs[1].Err.Error(), "must be a non-negative integer in range of uint16"; actual != expected {
+		t.Errorf("Params.Bind => error %q, want %q", actual, expected)
+	}
+	if errs.StatusCode() != 400 {
+		t.Errorf("ParamErrors.StatusCode() => %v, want %v", errs.StatusCode(), 400)
+	}
+
+	if err := (denco.Params{}).Bind(actual); err == nil {
+		t.Errorf("Params.Bind with non-pointer => nil, want error")
+	}
+}
@@ -0,0 +1,117 @@
+// Package ratelimit provides token bucket rate limiting for handlers of denco.Mux.
+//
+// Buckets are keyed by the matched route and the values of selected path parameters,
+// or the client IP address if no parameter is selected. For example, the following limits
+// POST /t/:tenant/jobs to 10 requests per second for each tenant.
+//
+//	limiter := ratelimit.New(10, 10, "tenant")
+//	mux.POST("/t/:tenant/jobs", limiter.Wrap(createJob))
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/naoina/denco"
+)
+
+// Limit represents a rate of a token bucket.
+type Limit struct {
+	// Rate is the number of tokens that are added to the bucket per second.
+	Rate float64
+
+	// Burst is the capacity of the bucket.
+	Burst int
+}
+
+// Store stores token buckets.
+type Store interface {
+	// Take takes a token from the bucket of key.
+	// If the bucket is empty, Take returns false and the duration until a token is available.
+	Take(ctx context.Context, key string, limit Limit) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// Limiter limits the rate of requests to handlers.
+type Limiter struct {
+	Limit Limit
+
+	// Params is the names of path parameters to key buckets.
+	// If empty, buckets are keyed by the client IP address.
+	Params []string
+
+	// ClientIP returns the client IP address of r.
+	// If nil, the host of r.RemoteAddr is used.
+	ClientIP func(r *http.Request) string
+
+	// Store is the store of buckets. New sets an in-memory store.
+	// If Store returns an error, the request is allowed.
+	Store Store
+
+	// LimitExceeded is called when the request is limited.
+	// If nil, the response is "429 Too Many Requests" with Retry-After header.
+	LimitExceeded func(w http.ResponseWriter, r *http.Request, retryAfter time.Duration)
+}
+
+// New returns a new Limiter that allows rate requests per second with burst
+// for each route and the values of params, with an in-memory store.
+func New(rate float64, burst int, params ...string) *Limiter {
+	return &Limiter{
+		Limit:  Limit{Rate: rate, Burst: burst},
+		Params: params,
+		Store:  NewMemoryStore(),
+	}
+}
+
+// Wrap returns a HandlerFunc that limits the rate of requests to fn.
+// Buckets are separated by routes, so a Limiter can be used for multiple routes.
+func (l *Limiter) Wrap(fn denco.HandlerFunc) denco.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		allowed, retryAfter, err := l.Store.Take(r.Context(), l.key(r, params), l.Limit)
+		if err != nil || allowed {
+			fn(w, r, params)
+			return
+		}
+		if l.LimitExceeded != nil {
+			l.LimitExceeded(w, r, retryAfter)
+			return
+		}
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10))
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	}
+}
+
+// key returns the key of the bucket for r such as "POST /t/:tenant/jobs\x00acme".
+func (l *Limiter) key(r *http.Request, params denco.Params) string {
+	var b strings.Builder
+	if route := denco.RouteFromContext(r.Context()); route != nil {
+		b.WriteString(route.Method)
+		b.WriteByte(' ')
+		b.WriteString(route.Host)
+		b.WriteString(route.Path)
+	}
+	if len(l.Params) == 0 {
+		b.WriteByte(0)
+		b.WriteString(l.clientIP(r))
+		return b.String()
+	}
+	for _, name := range l.Params {
+		b.WriteByte(0)
+		b.WriteString(params.Get(name))
+	}
+	return b.String()
+}
+
+func (l *Limiter) clientIP(r *http.Request) string {
+	if l.ClientIP != nil {
+		return l.ClientIP(r)
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/naoina/denco"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestLimiter(t *testing.T) {
+	clock := &testClock{now: time.Date(2014, 1, 6, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	tenant := New(1, 2, "tenant")
+	tenant.Store = store
+	ip := New(1, 1)
+	ip.Store = store
+	mux := denco.NewMux()
+	ok := func(w http.ResponseWriter, r *http.Request, params denco.Params) {}
+	handler, err := mux.Build([]denco.Handler{
+		mux.POST("/t/:tenant/jobs", tenant.Wrap(ok)),
+		mux.GET("/t/:tenant/jobs", ok).Wrap(tenant.Wrap),
+		mux.GET("/status", ip.Wrap(ok)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range []struct {
+		advance            time.Duration
+		method, path, addr string
+		status             int
+		retryAfter         string
+	}{
+		{0, "POST", "/t/acme/jobs", "192.0.2.1:1", 200, ""},
+		{0, "POST", "/t/acme/jobs", "192.0.2.2:1", 200, ""},
+		{0, "POST", "/t/acme/jobs", "192.0.2.3:1", 429, "1"},
+		{0, "POST", "/t/other/jobs", "192.0.2.1:1", 200, ""},
+		{0, "GET", "/t/acme/jobs", "192.0.2.1:1", 200, ""},
+		{500 * time.Millisecond, "POST", "/t/acme/jobs", "192.0.2.1:1", 429, "1"},
+		{500 * time.Millisecond, "POST", "/t/acme/jobs", "192.0.2.1:1", 200, ""},
+		{0, "GET", "/status", "192.0.2.1:1", 200, ""},
+		{0, "GET", "/status", "192.0.2.1:2", 429, "1"},
+		{0, "GET", "/status", "192.0.2.2:1", 200, ""},
+	} {
+		clock.now = clock.now.Add(v.advance)
+		req := httptest.NewRequest(v.method, v.path, nil)
+		req.RemoteAddr = v.addr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != v.status {
+			t.Errorf("#%d %s %s from %s => status %v, want %v", i, v.method, v.path, v.addr, w.Code, v.status)
+		}
+		if actual := w.Header().Get("Retry-After"); actual != v.retryAfter {
+			t.Errorf("#%d %s %s from %s => Retry-After %q, want %q", i, v.method, v.path, v.addr, actual, v.retryAfter)
+		}
+	}
+}
+
+type errorStore struct{}
+
+func (errorStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	return false, 0, errors.New("unavailable")
+}
+
+func TestLimiter_storeError(t *testing.T) {
+	l := New(1, 1)
+	l.Store = errorStore{}
+	called := false
+	l.Wrap(func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		called = true
+	})(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	if !called {
+		t.Errorf("handler isn't called when the store fails")
+	}
+}
+
+func TestLimiter_LimitExceeded(t *testing.T) {
+	l := New(2, 1)
+	var retryAfter time.Duration
+	l.LimitExceeded = func(w http.ResponseWriter, r *http.Request, d time.Duration) {
+		retryAfter = d
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	store := NewMemoryStore()
+	store.now = func() time.Time { return time.Date(2014, 1, 6, 0, 0, 0, 0, time.UTC) }
+	l.Store = store
+	fn := l.Wrap(func(w http.ResponseWriter, r *http.Request, params denco.Params) {})
+	fn(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	w := httptest.NewRecorder()
+	fn(w, httptest.NewRequest("GET", "/", nil), nil)
+	if w.Code != http.StatusServiceUnavailable || retryAfter != 500*time.Millisecond {
+		t.Errorf("LimitExceeded => status %v, retryAfter %v, want %v, %v", w.Code, retryAfter, http.StatusServiceUnavailable, 500*time.Millisecond)
+	}
+}
+
+func TestMemoryStore_sweep(t *testing.T) {
+	clock := &testClock{now: time.Date(2014, 1, 6, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	limit := Limit{Rate: 1, Burst: 1}
+	for _, key := range []string{"a", "b"} {
+		store.Take(context.Background(), key, limit)
+	}
+	if len(store.buckets) != 2 {
+		t.Fatalf("len(buckets) => %v, want 2", len(store.buckets))
+	}
+	clock.now = clock.now.Add(sweepInterval)
+	store.Take(context.Background(), "c", limit)
+	if len(store.buckets) != 1 || store.buckets["c"] == nil {
+		t.Errorf("buckets after sweep => %v, want only c", store.buckets)
+	}
+}
@@ -0,0 +1,127 @@
+package denco
+
+import (
+	"context"
+	"net/http"
+)
+
+// Route represents the route that matched a request.
+type Route struct {
+	// Method is the method of the handler.
+	// It is empty if the handler matches any method, such as the handlers returned by Mux.Mount.
+	Method string
+
+	// Host is the host of the handler such as ":tenant.example.com".
+	Host string
+
+	// Path is the routing path of the handler such as "/user/:id".
+	// It is empty if no handler matched the request.
+	Path string
+
+	// Meta is the metadata of the handler.
+	Meta Meta
+
+	// Params is the path parameters of the request.
+	Params Params
+
+	// prefix is the path of Mux.Mount while the request is served by the mounted http.Handler.
+	prefix string
+
+	// hooks are called when the route is set. See TrackRouteFunc.
+	hooks []func(route *Route)
+}
+
+// set sets the route of h and params to route.
+// If route has prefix, h is a handler of a mounted Mux and is joined to route.
+func (route *Route) set(h *Handler, params Params) {
+	route.Params = params
+	defer route.callHooks()
+	if route.prefix == "" {
+		route.Method, route.Host, route.Path, route.Meta = h.Method, h.Host, h.Path, h.Meta
+		return
+	}
+	if h.Method != "" {
+		route.Method = h.Method
+	}
+	if h.Host != "" {
+		route.Host = h.Host
+	}
+	route.Path = route.prefix + h.Path
+	if h.Meta != nil {
+		route.Meta = h.Meta
+	}
+}
+
+func (route *Route) callHooks() {
+	for _, fn := range route.hooks {
+		fn(route)
+	}
+}
+
+// fork returns a copy of route that has no hooks of route, and whether the copy has been set.
+// The copy is used by a handler that runs in another goroutine.
+func (route *Route) fork() (*Route, *bool) {
+	inner := new(Route)
+	*inner = *route
+	rerouted := new(bool)
+	inner.hooks = []func(*Route){func(*Route) { *rerouted = true }}
+	return inner, rerouted
+}
+
+// merge sets the route of inner that is returned by fork to route, and calls the hooks of route.
+func (route *Route) merge(inner *Route) {
+	route.Method, route.Host, route.Path, route.Meta, route.Params = inner.Method, inner.Host, inner.Path, inner.Meta, inner.Params
+	route.callHooks()
+}
+
+type routeContextKey struct{}
+
+// RouteFromContext returns the Route that matched the request.
+// The context of the request that passed to a handler always has the Route.
+// RouteFromContext returns nil if ctx has no Route.
+func RouteFromContext(ctx context.Context) *Route {
+	route, _ := ctx.Value(routeContextKey{}).(*Route)
+	return route
+}
+
+// MetaFromContext returns the metadata of the route that matched the request.
+func MetaFromContext(ctx context.Context) Meta {
+	if route := RouteFromContext(ctx); route != nil {
+		return route.Meta
+	}
+	return nil
+}
+
+// TrackRoute returns a shallow copy of r that has an empty Route in its context,
+// and the Route. The Route is filled when the request is routed by a http.Handler
+// that built by Mux.Build, so middlewares that wrap the http.Handler can inspect
+// the matched route after calling it, e.g.
+//
+//	func Middleware(next http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			r, route := denco.TrackRoute(r)
+//			next.ServeHTTP(w, r)
+//			log.Printf("%s %s", r.Method, route.Path) // e.g. "GET /user/:id".
+//		})
+//	}
+//
+// If r already has a Route, TrackRoute returns r and the Route as is.
+func TrackRoute(r *http.Request) (*http.Request, *Route) {
+	if route := RouteFromContext(r.Context()); route != nil {
+		return r, route
+	}
+	route := &Route{}
+	return r.WithContext(context.WithValue(r.Context(), routeContextKey{}, route)), route
+}
+
+// TrackRouteFunc is the same as TrackRoute, but fn is called with the Route each time
+// the request is routed, before the handler is called. It allows middlewares to
+// observe the route while the handler is running. fn may be called more than once
+// if the request is routed by a Mux mounted by Mux.Mount, and the Route has the joined path then.
+// If the handler has a time limit, fn is called for the routing in the handler after the handler
+// returns, and is not called if the time limit is exceeded. See Mux.Timeout.
+func TrackRouteFunc(r *http.Request, fn func(route *Route)) (*http.Request, *Route) {
+	r, route := TrackRoute(r)
+	route.hooks = append(route.hooks, fn)
+	return r, route
+}
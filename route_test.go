@@ -0,0 +1,115 @@
+package denco_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/naoina/denco"
+)
+
+func TestRouteFromContext(t *testing.T) {
+	routeHandlerFunc := func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		route := denco.RouteFromContext(r.Context())
+		fmt.Fprintf(w, "%s %s%s", route.Method, route.Host, route.Path)
+	}
+	sub := denco.NewMux()
+	subHandler, err := sub.Build([]denco.Handler{
+		sub.POST("/users/:name", routeHandlerFunc),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	staff := denco.NewMux()
+	staff.NotFound = denco.NotFound
+	staffHandler, err := staff.Build([]denco.Handler{
+		staff.POST("/users/:name", routeHandlerFunc),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested := denco.NewMux()
+	nestedHandler, err := nested.Build(nested.Mount("/v1", subHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := denco.NewMux()
+	handlers := []denco.Handler{
+		mux.GET("/user/:id", routeHandlerFunc),
+	}
+	handlers = append(handlers, mux.Host(":tenant.example.com", mux.GET("/user/:id", routeHandlerFunc))...)
+	handlers = append(handlers, mux.Mount("/admin", subHandler)...)
+	handlers = append(handlers, mux.Mount("/staff", staffHandler)...)
+	handlers = append(handlers, mux.Mount("/api", nestedHandler)...)
+	handler, err := mux.Build(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tracked []denco.Route
+	middleware := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, route := denco.TrackRoute(r)
+		handler.ServeHTTP(w, r)
+		tracked = append(tracked, *route)
+	})
+	for _, v := range []struct {
+		method, host, path string
+		expected           string
+		route              denco.Route
+	}{
+		{"GET", "other.com", "/user/1", "GET /user/:id", denco.Route{Method: "GET", Path: "/user/:id", Params: denco.Params{{Name: "id", Value: "1"}}}},
+		{"GET", "acme.example.com", "/user/1", "GET :tenant.example.com/user/:id", denco.Route{Method: "GET", Host: ":tenant.example.com", Path: "/user/:id", Params: denco.Params{{Name: "tenant", Value: "acme"}, {Name: "id", Value: "1"}}}},
+		{"POST", "other.com", "/admin/users/alice", "POST /admin/users/:name", denco.Route{Method: "POST", Path: "/admin/users/:name", Params: denco.Params{{Name: "name", Value: "alice"}}}},
+		{"POST", "other.com", "/staff/users/alice", "POST /staff/users/:name", denco.Route{Method: "POST", Path: "/staff/users/:name", Params: denco.Params{{Name: "name", Value: "alice"}}}},
+		{"POST", "other.com", "/api/v1/users/alice", "POST /api/v1/users/:name", denco.Route{Method: "POST", Path: "/api/v1/users/:name", Params: denco.Params{{Name: "name", Value: "alice"}}}},
+		{"GET", "other.com", "/missing", "404 page not found\n", denco.Route{}},
+	} {
+		req := httptest.NewRequest(v.method, v.path, nil)
+		req.Host = v.host
+		w := httptest.NewRecorder()
+		middleware.ServeHTTP(w, req)
+		if actual := w.Body.String(); actual != v.expected {
+			t.Errorf(`%s "%s%s" => %#v, want %#v`, v.method, v.host, v.path, actual, v.expected)
+		}
+		if actual := tracked[len(tracked)-1]; !reflect.DeepEqual(actual, v.route) {
+			t.Errorf(`%s "%s%s" => tracked %#v, want %#v`, v.method, v.host, v.path, actual, v.route)
+		}
+	}
+}
+
+func TestTrackRoute(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	if route := denco.RouteFromContext(req.Context()); route != nil {
+		t.Errorf("RouteFromContext => %#v, want nil", route)
+	}
+	r1, route1 := denco.TrackRoute(req)
+	r2, route2 := denco.TrackRoute(r1)
+	if r1 == req || r2 != r1 || route1 != route2 {
+		t.Errorf("TrackRoute => (%p, %p), (%p, %p)", r1, route1, r2, route2)
+	}
+}
+
+func TestTrackRouteFunc(t *testing.T) {
+	sub := denco.NewMux()
+	subHandler, err := sub.Build([]denco.Handler{
+		sub.GET("/users/:name", func(w http.ResponseWriter, r *http.Request, params denco.Params) {}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := denco.NewMux()
+	handler, err := mux.Build(mux.Mount("/admin", subHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	r, _ := denco.TrackRouteFunc(httptest.NewRequest("GET", "/admin/users/alice", nil), func(route *denco.Route) {
+		paths = append(paths, route.Path)
+	})
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if expected := []string{"/admin/*rest", "/admin/users/:name"}; !reflect.DeepEqual(paths, expected) {
+		t.Errorf("TrackRouteFunc => called with %#v, want %#v", paths, expected)
+	}
+}
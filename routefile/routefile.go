@@ -0,0 +1,199 @@
+// Package routefile loads routes of denco.Mux from a route file.
+//
+// A route file is a JSON array of routes:
+//
+//	[
+//	  {"method": "GET", "path": "/users/:id", "handler": "getUser", "middlewares": ["auth"]},
+//	  {"method": "POST", "path": "/users", "handler": "createUser", "meta": {"owner": "team-a"}}
+//	]
+//
+// The names of handlers and middlewares are resolved against a Registry.
+//
+// Parse and Registry.Load read JSON only, in order not to depend on external packages.
+// Route files in other formats such as YAML or TOML can be decoded into []Route by
+// the caller, and then passed to Registry.Resolve. Route has the struct tags for them,
+// and the caller can set Route.Line to report errors with line numbers.
+package routefile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/naoina/denco"
+)
+
+// Route represents an entry of a route file.
+type Route struct {
+	// Method is an HTTP method.
+	Method string `json:"method" yaml:"method" toml:"method"`
+
+	// Path is a routing path of denco.
+	Path string `json:"path" yaml:"path" toml:"path"`
+
+	// Handler is a name of the handler function in Registry.
+	Handler string `json:"handler" yaml:"handler" toml:"handler"`
+
+	// Middlewares is a list of names of the middlewares in Registry.
+	// The first middleware is the outermost.
+	Middlewares []string `json:"middlewares,omitempty" yaml:"middlewares,omitempty" toml:"middlewares,omitempty"`
+
+	// Meta is arbitrary metadata of the route.
+	// It is set to Meta of the handler.
+	Meta map[string]string `json:"meta,omitempty" yaml:"meta,omitempty" toml:"meta,omitempty"`
+
+	// Line is a line number of the route in the route file.
+	// It is set by Parse, and is used by the errors of Registry.Resolve.
+	Line int `json:"-" yaml:"-" toml:"-"`
+}
+
+// Middleware is a function that wraps a handler function.
+type Middleware func(denco.HandlerFunc) denco.HandlerFunc
+
+// Registry is a set of named handler functions and middlewares.
+type Registry struct {
+	Handlers    map[string]denco.HandlerFunc
+	Middlewares map[string]Middleware
+}
+
+// NewRegistry returns a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		Handlers:    make(map[string]denco.HandlerFunc),
+		Middlewares: make(map[string]Middleware),
+	}
+}
+
+// Load reads a route file in JSON from r and returns handlers for Mux.Build.
+// If there are bad entries, Load returns an ErrorList that contains the errors for all of them.
+func (reg *Registry) Load(mux *denco.Mux, r io.Reader) ([]denco.Handler, error) {
+	routes, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return reg.Resolve(mux, routes)
+}
+
+// Resolve returns handlers for Mux.Build from routes.
+// If there are bad entries, Resolve returns an ErrorList that contains the errors for all of them
+// with Line of the routes.
+func (reg *Registry) Resolve(mux *denco.Mux, routes []Route) ([]denco.Handler, error) {
+	var (
+		handlers []denco.Handler
+		errs     ErrorList
+	)
+	for _, route := range routes {
+		h, err := reg.handler(mux, route)
+		if err != nil {
+			errs = append(errs, &Error{Line: route.Line, Err: err})
+			continue
+		}
+		handlers = append(handlers, h)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return handlers, nil
+}
+
+func (reg *Registry) handler(mux *denco.Mux, route Route) (denco.Handler, error) {
+	if route.Method == "" || strings.ToUpper(route.Method) != route.Method {
+		return denco.Handler{}, fmt.Errorf("invalid method `%v'", route.Method)
+	}
+	if !strings.HasPrefix(route.Path, "/") {
+		return denco.Handler{}, fmt.Errorf("path `%v' must begin with `/'", route.Path)
+	}
+	if err := denco.New().Build([]denco.Record{denco.NewRecord(route.Path, nil)}); err != nil {
+		return denco.Handler{}, err
+	}
+	fn, found := reg.Handlers[route.Handler]
+	if !found {
+		return denco.Handler{}, fmt.Errorf("unknown handler `%v'", route.Handler)
+	}
+	for i := len(route.Middlewares) - 1; i >= 0; i-- {
+		mw, found := reg.Middlewares[route.Middlewares[i]]
+		if !found {
+			return denco.Handler{}, fmt.Errorf("unknown middleware `%v'", route.Middlewares[i])
+		}
+		fn = mw(fn)
+	}
+	h := mux.Handler(route.Method, route.Path, fn)
+	for k, v := range route.Meta {
+		h = h.WithMeta(k, v)
+	}
+	return h, nil
+}
+
+// Parse parses a route file in JSON from r.
+// Line of each route will be set to the line number where the route begins.
+func Parse(r io.Reader) ([]Route, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if tok, err := dec.Token(); err != nil {
+		return nil, syntaxError(data, err)
+	} else if tok != json.Delim('[') {
+		return nil, &Error{Line: lineAt(data, 0), Err: fmt.Errorf("route file must be an array of routes")}
+	}
+	var routes []Route
+	for dec.More() {
+		line := lineAt(data, dec.InputOffset())
+		var route Route
+		if err := dec.Decode(&route); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				return nil, syntaxError(data, err)
+			}
+			return nil, &Error{Line: line, Err: err}
+		}
+		route.Line = line
+		routes = append(routes, route)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, syntaxError(data, err)
+	}
+	return routes, nil
+}
+
+// lineAt returns the line number of the first significant character at or after offset.
+func lineAt(data []byte, offset int64) int {
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,", data[offset]) >= 0 {
+		offset++
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+func syntaxError(data []byte, err error) error {
+	if e, ok := err.(*json.SyntaxError); ok {
+		return &Error{Line: bytes.Count(data[:e.Offset], []byte("\n")) + 1, Err: err}
+	}
+	return &Error{Line: bytes.Count(data, []byte("\n")) + 1, Err: err}
+}
+
+// Error represents an error of a route file.
+type Error struct {
+	// Line is a line number where the error occurred.
+	Line int
+
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("routefile: line %d: %v", e.Line, e.Err)
+}
+
+// ErrorList is a list of errors of a route file.
+type ErrorList []*Error
+
+func (errs ErrorList) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
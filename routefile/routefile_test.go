@@ -0,0 +1,146 @@
+package routefile_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/routefile"
+)
+
+func testRegistry() *routefile.Registry {
+	reg := routefile.NewRegistry()
+	reg.Handlers["user"] = func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		fmt.Fprintf(w, "user %s", params.Get("id"))
+		if owner := denco.MetaFromContext(r.Context()).String("owner"); owner != "" {
+			fmt.Fprintf(w, " owned by %s", owner)
+		}
+	}
+	for _, name := range []string{"a", "b"} {
+		name := name
+		reg.Middlewares[name] = func(next denco.HandlerFunc) denco.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+				fmt.Fprintf(w, "%s:", name)
+				next(w, r, params)
+			}
+		}
+	}
+	return reg
+}
+
+func TestRegistry_Load(t *testing.T) {
+	mux := denco.NewMux()
+	handlers, err := testRegistry().Load(mux, strings.NewReader(`[
+  {"method": "GET", "path": "/users/:id", "handler": "user", "middlewares": ["a", "b"]},
+  {"method": "POST", "path": "/users/:id", "handler": "user", "meta": {"owner": "team-a"}}
+]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, err := mux.Build(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		method, path, expected string
+	}{
+		{"GET", "/users/alice", "a:b:user alice"},
+		{"POST", "/users/bob", "user bob owned by team-a"},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(v.method, v.path, nil))
+		if actual := w.Body.String(); actual != v.expected {
+			t.Errorf(`%s "%s" => %#v, want %#v`, v.method, v.path, actual, v.expected)
+		}
+	}
+}
+
+func TestRegistry_Load_withBadEntries(t *testing.T) {
+	_, err := testRegistry().Load(denco.NewMux(), strings.NewReader(`[
+  {"method": "GET", "path": "/users/:id", "handler": "user"},
+  {"method": "get", "path": "/users", "handler": "user"},
+  {
+    "method": "GET",
+    "path": "/:id/:id",
+    "handler": "user"
+  },
+  {"method": "GET", "path": "users", "handler": "user"},
+  {"method": "GET", "path": "/users", "handler": "unknown"},
+  {"method": "GET", "path": "/users", "handler": "user", "middlewares": ["c"]}
+]`))
+	errs, ok := err.(routefile.ErrorList)
+	if !ok {
+		t.Fatalf("Registry.Load => %#v, want routefile.ErrorList", err)
+	}
+	var actual []int
+	for _, e := range errs {
+		actual = append(actual, e.Line)
+	}
+	expected := []int{3, 4, 9, 10, 11}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Registry.Load => lines %v, want %v; %v", actual, expected, err)
+	}
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	mux := denco.NewMux()
+	_, err := testRegistry().Resolve(mux, []routefile.Route{
+		{Method: "GET", Path: "/users/:id", Handler: "user", Middlewares: []string{"a"}, Line: 1},
+		{Method: "GET", Path: "/users", Handler: "unknown", Line: 5},
+	})
+	if errs, ok := err.(routefile.ErrorList); !ok || len(errs) != 1 || errs[0].Line != 5 {
+		t.Errorf("Registry.Resolve => %#v, want routefile.ErrorList with line 5", err)
+	}
+	handlers, err := testRegistry().Resolve(mux, []routefile.Route{
+		{Method: "GET", Path: "/users/:id", Handler: "user", Middlewares: []string{"a"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(handlers) != 1 || handlers[0].Method != "GET" || handlers[0].Path != "/users/:id" {
+		t.Errorf("Registry.Resolve => %#v", handlers)
+	}
+}
+
+func TestParse(t *testing.T) {
+	routes, err := routefile.Parse(strings.NewReader(`[
+  {"method": "GET", "path": "/", "handler": "index", "meta": {"owner": "team-a"}},
+
+  {"method": "POST", "path": "/", "handler": "create"}
+]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []routefile.Route{
+		{Method: "GET", Path: "/", Handler: "index", Meta: map[string]string{"owner": "team-a"}, Line: 2},
+		{Method: "POST", Path: "/", Handler: "create", Line: 4},
+	}
+	if !reflect.DeepEqual(routes, expected) {
+		t.Errorf("Parse => %#v, want %#v", routes, expected)
+	}
+
+	for _, v := range []struct {
+		input string
+		line  int
+	}{
+		{"{}", 1},
+		{"[\n  {\"method\": \"GET\",}\n]", 2},
+		{"[\n  {\"method\": 1}\n]", 2},
+		{"[\n  {\"unknown\": \"GET\"}\n]", 2},
+		{"[\n  {}\n", 3},
+	} {
+		_, err := routefile.Parse(strings.NewReader(v.input))
+		e, ok := err.(*routefile.Error)
+		if !ok {
+			t.Errorf("Parse(%q) => %#v, want *routefile.Error", v.input, err)
+			continue
+		}
+		if e.Line != v.line {
+			t.Errorf("Parse(%q) => line %v, want %v; %v", v.input, e.Line, v.line, e)
+		}
+	}
+}
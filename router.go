@@ -28,16 +28,42 @@ type Router struct {
 	// By default, SizeHint will be determined from given records to Build.
 	SizeHint int
 
-	static map[string]interface{}
+	// ParamCharacter is a special character for path parameter.
+	// By default, it is denco.ParamCharacter.
+	ParamCharacter byte
+
+	// WildcardCharacter is a special character for wildcard path parameter.
+	// By default, it is denco.WildcardCharacter.
+	WildcardCharacter byte
+
+	// TerminationCharacter is a special character for end of path.
+	// It is used internally and cannot be used in keys and paths.
+	// By default, it is denco.TerminationCharacter.
+	TerminationCharacter byte
+
+	// Separator is a character that separates the segments of a path.
+	// The value of path parameter will never contain Separator.
+	// By default, it is '/'.
+	Separator byte
+
+	// static has nodes for keys that have no path parameters.
+	// A node holds both data and metadata so that Lookup of a static key costs a single map access.
+	static map[string]*node
 	param  *doubleArray
 }
 
 // New returns a new Router.
+// The special characters and the separator can be changed before Build, e.g. for routing
+// dotted keys such as "orders.:region.created", set Separator to '.'.
 func New() *Router {
 	return &Router{
-		SizeHint: -1,
-		static:   make(map[string]interface{}),
-		param:    newDoubleArray(),
+		SizeHint:             -1,
+		ParamCharacter:       ParamCharacter,
+		WildcardCharacter:    WildcardCharacter,
+		TerminationCharacter: TerminationCharacter,
+		Separator:            '/',
+		static:               make(map[string]*node),
+		param:                newDoubleArray(),
 	}
 }
 
@@ -45,9 +71,35 @@ func New() *Router {
 // params is a slice of the Param that arranged in the order in which parameters appeared.
 // e.g. when built routing path is "/path/to/:id/:name" and given path is "/path/to/1/alice". params order is [{"id": "1"}, {"name": "alice"}], not [{"name": "alice"}, {"id": "1"}].
 func (rt *Router) Lookup(path string) (data interface{}, params Params, found bool) {
-	if data, found := rt.static[path]; found {
-		return data, nil, true
+	if nd, found := rt.static[path]; found {
+		return nd.data, nil, true
+	}
+	nd, params, found := rt.lookupParam(path)
+	if !found {
+		return nil, nil, false
+	}
+	return nd.data, params, true
+}
+
+// LookupMeta is the same as Lookup, but also returns the metadata of the record that associated with path.
+func (rt *Router) LookupMeta(path string) (data interface{}, meta Meta, params Params, found bool) {
+	nd, params, found := rt.lookup(path)
+	if !found {
+		return nil, nil, nil, false
 	}
+	return nd.data, nd.meta, params, true
+}
+
+// lookup returns the node and path parameters that associated with path.
+func (rt *Router) lookup(path string) (*node, Params, bool) {
+	if nd, found := rt.static[path]; found {
+		return nd, nil, true
+	}
+	return rt.lookupParam(path)
+}
+
+// lookupParam returns the node and path parameters that associated with path from the Double-Array.
+func (rt *Router) lookupParam(path string) (*node, Params, bool) {
 	if len(rt.param.node) == 1 {
 		return nil, nil, false
 	}
@@ -58,12 +110,25 @@ func (rt *Router) Lookup(path string) (data interface{}, params Params, found bo
 	for i := 0; i < len(params); i++ {
 		params[i].Name = nd.paramNames[i]
 	}
-	return nd.data, params, true
+	return nd, params, true
 }
 
 // Build builds URL router from records.
 func (rt *Router) Build(records []Record) error {
-	statics, params := makeRecords(records)
+	recs := make([]RecordMeta, len(records))
+	for i, r := range records {
+		recs[i].Record = r
+	}
+	return rt.BuildMeta(recs)
+}
+
+// BuildMeta is the same as Build, but builds URL router from records that have metadata.
+// The metadata is returned by Router.LookupMeta.
+func (rt *Router) BuildMeta(records []RecordMeta) error {
+	if err := rt.setChars(); err != nil {
+		return err
+	}
+	statics, params := rt.param.makeRecords(records)
 	if len(params) > MaxSize {
 		return fmt.Errorf("denco: too many records")
 	}
@@ -71,8 +136,8 @@ func (rt *Router) Build(records []Record) error {
 		rt.SizeHint = 0
 		for _, p := range params {
 			size := 0
-			for _, k := range p.Key {
-				if k == ParamCharacter || k == WildcardCharacter {
+			for i := 0; i < len(p.Key); i++ {
+				if k := p.Key[i]; k == rt.ParamCharacter || k == rt.WildcardCharacter {
 					size++
 				}
 			}
@@ -82,7 +147,7 @@ func (rt *Router) Build(records []Record) error {
 		}
 	}
 	for _, r := range statics {
-		rt.static[r.Key] = r.Value
+		rt.static[r.Key] = &node{data: r.Value, index: r.index, meta: r.Meta}
 	}
 	if err := rt.param.build(params, 1, 0, make(map[int]struct{})); err != nil {
 		return err
@@ -90,6 +155,26 @@ func (rt *Router) Build(records []Record) error {
 	return nil
 }
 
+// setChars sets the special characters to the Double-Array.
+func (rt *Router) setChars() error {
+	chars := []byte{rt.ParamCharacter, rt.WildcardCharacter, rt.TerminationCharacter, rt.Separator}
+	for i, c := range chars {
+		if c == 0 {
+			return fmt.Errorf("denco: special characters and separator must not be NUL")
+		}
+		for _, c2 := range chars[i+1:] {
+			if c == c2 {
+				return fmt.Errorf("denco: special characters and separator must be distinct, but `%c' is duplicated", c)
+			}
+		}
+	}
+	rt.param.paramChar = rt.ParamCharacter
+	rt.param.wildcardChar = rt.WildcardCharacter
+	rt.param.termChar = rt.TerminationCharacter
+	rt.param.sep = rt.Separator
+	return nil
+}
+
 // Param represents name and value of path parameter.
 type Param struct {
 	Name  string
@@ -113,12 +198,22 @@ func (ps Params) Get(name string) string {
 type doubleArray struct {
 	bc   []baseCheck
 	node []*node
+
+	// Special characters and separator. See Router.
+	paramChar    byte
+	wildcardChar byte
+	termChar     byte
+	sep          byte
 }
 
 func newDoubleArray() *doubleArray {
 	return &doubleArray{
-		bc:   []baseCheck{0},
-		node: []*node{nil}, // A start index is adjusting to 1 because 0 will be used as a mark of non-existent node.
+		bc:           []baseCheck{0},
+		node:         []*node{nil}, // A start index is adjusting to 1 because 0 will be used as a mark of non-existent node.
+		paramChar:    ParamCharacter,
+		wildcardChar: WildcardCharacter,
+		termChar:     TerminationCharacter,
+		sep:          '/',
 	}
 }
 
@@ -187,7 +282,7 @@ func (da *doubleArray) lookup(path string, params []Param, idx int) (*node, []Pa
 			goto BACKTRACKING
 		}
 	}
-	if next := nextIndex(da.bc[idx].Base(), TerminationCharacter); next < len(da.bc) && da.bc[next].Check() == TerminationCharacter {
+	if next := nextIndex(da.bc[idx].Base(), da.termChar); next < len(da.bc) && da.bc[next].Check() == da.termChar {
 		return da.node[da.bc[next].Base()], params, true
 	}
 	if len(indices) > 0 {
@@ -198,18 +293,18 @@ BACKTRACKING:
 	for j := len(indices) - 1; j >= 0; j-- {
 		i, idx := int(indices[j]>>32), int(indices[j]&0xffffffff)
 		if da.bc[idx].IsSingleParam() {
-			idx := nextIndex(da.bc[idx].Base(), ParamCharacter)
+			idx := nextIndex(da.bc[idx].Base(), da.paramChar)
 			if idx >= len(da.bc) {
 				break
 			}
-			next := NextSeparator(path, i)
+			next := nextSeparator(path, i, da.sep, da.termChar)
 			params := append(params, Param{Value: path[i:next]})
 			if nd, params, found := da.lookup(path[next:], params, idx); found {
 				return nd, params, true
 			}
 		}
 		if da.bc[idx].IsWildcardParam() {
-			idx := nextIndex(da.bc[idx].Base(), WildcardCharacter)
+			idx := nextIndex(da.bc[idx].Base(), da.wildcardChar)
 			params := append(params, Param{Value: path[i:]})
 			return da.node[da.bc[idx].Base()], params, true
 		}
@@ -238,9 +333,9 @@ func (da *doubleArray) build(srcs []*record, idx, depth int, usedBase map[int]st
 	for _, sib := range siblings {
 		records := srcs[sib.start:sib.end]
 		switch sib.c {
-		case ParamCharacter:
+		case da.paramChar:
 			for _, r := range records {
-				next := NextSeparator(r.Key, depth+1)
+				next := nextSeparator(r.Key, depth+1, da.sep, da.termChar)
 				name := r.Key[depth+1 : next]
 				r.paramNames = append(r.paramNames, name)
 				r.Key = r.Key[next:]
@@ -249,7 +344,7 @@ func (da *doubleArray) build(srcs []*record, idx, depth int, usedBase map[int]st
 			if err := da.build(records, nextIndex(base, sib.c), 0, usedBase); err != nil {
 				return err
 			}
-		case WildcardCharacter:
+		case da.wildcardChar:
 			r := records[0]
 			name := r.Key[depth+1 : len(r.Key)-1]
 			r.paramNames = append(r.paramNames, name)
@@ -333,6 +428,12 @@ func (da *doubleArray) arrange(records []*record, idx, depth int, usedBase map[i
 type node struct {
 	data interface{}
 
+	// Index of the record in the records given to Build.
+	index int
+
+	// Metadata of the record.
+	meta Meta
+
 	// Names of path parameters.
 	paramNames []string
 }
@@ -346,7 +447,7 @@ func makeNode(r *record) (*node, error) {
 		}
 		dups[name] = true
 	}
-	return &node{data: r.Value, paramNames: r.paramNames}, nil
+	return &node{data: r.Value, index: r.index, meta: r.Meta, paramNames: r.paramNames}, nil
 }
 
 // sibling represents an intermediate data of build for Double-Array.
@@ -408,6 +509,32 @@ type Record struct {
 	Value interface{}
 }
 
+// RecordMeta represents a record data that has metadata for router construction.
+// See Router.BuildMeta.
+type RecordMeta struct {
+	Record
+
+	// Meta is arbitrary metadata of the record.
+	// It is returned by Router.LookupMeta.
+	Meta Meta
+}
+
+// Meta represents metadata of a route such as auth scopes, rate-limit classes and owners.
+type Meta map[string]interface{}
+
+// Get gets the value associated with the given key.
+// If there is no value associated with the key, Get returns nil.
+func (m Meta) Get(key string) interface{} {
+	return m[key]
+}
+
+// String gets the value associated with the given key as string.
+// If the value isn't a string, String returns "".
+func (m Meta) String(key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
 // NewRecord returns a new Record.
 func NewRecord(key string, value interface{}) Record {
 	return Record{
@@ -416,22 +543,33 @@ func NewRecord(key string, value interface{}) Record {
 	}
 }
 
+// NewRecordMeta returns a new RecordMeta.
+func NewRecordMeta(key string, value interface{}, meta Meta) RecordMeta {
+	return RecordMeta{
+		Record: NewRecord(key, value),
+		Meta:   meta,
+	}
+}
+
 // record represents a record that use to build the Double-Array.
 type record struct {
-	Record
+	RecordMeta
 	paramNames []string
+
+	// index is the index of the record in the records given to Build.
+	index int
 }
 
 // makeRecords returns the records that use to build Double-Arrays.
-func makeRecords(srcs []Record) (statics, params []*record) {
-	spChars := string([]byte{ParamCharacter, WildcardCharacter})
-	termChar := string(TerminationCharacter)
-	for _, r := range srcs {
+func (da *doubleArray) makeRecords(srcs []RecordMeta) (statics, params []*record) {
+	spChars := string([]byte{da.paramChar, da.wildcardChar})
+	termChar := string(da.termChar)
+	for i, r := range srcs {
 		if strings.ContainsAny(r.Key, spChars) {
 			r.Key += termChar
-			params = append(params, &record{Record: r})
+			params = append(params, &record{RecordMeta: r, index: i})
 		} else {
-			statics = append(statics, &record{Record: r})
+			statics = append(statics, &record{RecordMeta: r, index: i})
 		}
 	}
 	return statics, params
@@ -523,3 +523,73 @@ func TestParams_Get(t *testing.T) {
 		}
 	}
 }
+
+func TestRouter_Lookup_withCustomCharacters(t *testing.T) {
+	r := denco.New()
+	r.Separator = '.'
+	r.ParamCharacter = '+'
+	r.WildcardCharacter = '>'
+	r.TerminationCharacter = '$'
+	if err := r.Build([]denco.Record{
+		{Key: "orders.+region.created", Value: "created"},
+		{Key: "orders.+region.+id", Value: "order"},
+		{Key: "orders.eu.>rest", Value: "eu"},
+		{Key: "users/#1:a*b", Value: "static"},
+		{Key: "users/#1:a*b.+name", Value: "param"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []testcase{
+		{"orders.us.created", "created", []denco.Param{{Name: "region", Value: "us"}}, true},
+		{"orders.us/west.42", "order", []denco.Param{{Name: "region", Value: "us/west"}, {Name: "id", Value: "42"}}, true},
+		{"orders.eu.a.b", "eu", []denco.Param{{Name: "rest", Value: "a.b"}}, true},
+		{"users/#1:a*b", "static", nil, true},
+		{"users/#1:a*b.alice", "param", []denco.Param{{Name: "name", Value: "alice"}}, true},
+		{"orders.us", nil, nil, false},
+	} {
+		data, params, found := r.Lookup(v.path)
+		if !reflect.DeepEqual(data, v.value) || !reflect.DeepEqual(params, denco.Params(v.params)) || found != v.found {
+			t.Errorf("Router.Lookup(%q) => (%#v, %#v, %#v), want (%#v, %#v, %#v)", v.path, data, params, found, v.value, denco.Params(v.params), v.found)
+		}
+	}
+
+	for _, set := range []func(r *denco.Router){
+		func(r *denco.Router) { r.Separator = ':' },
+		func(r *denco.Router) { r.WildcardCharacter = '#' },
+		func(r *denco.Router) { r.TerminationCharacter = 0 },
+	} {
+		r := denco.New()
+		set(r)
+		if err := r.Build([]denco.Record{{Key: "/", Value: "root"}}); err == nil {
+			t.Errorf("Router.Build with invalid characters => nil, want error")
+		}
+	}
+}
+
+func TestRouter_LookupMeta(t *testing.T) {
+	r := denco.New()
+	if err := r.BuildMeta([]denco.RecordMeta{
+		{Record: denco.Record{"/", "root"}, Meta: denco.Meta{"owner": "team-a"}},
+		denco.NewRecordMeta("/user/:id", "user", denco.Meta{"scopes": []string{"read"}}),
+		{Record: denco.NewRecord("/about", "about")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path   string
+		value  interface{}
+		meta   denco.Meta
+		params denco.Params
+		found  bool
+	}{
+		{"/", "root", denco.Meta{"owner": "team-a"}, nil, true},
+		{"/user/alice", "user", denco.Meta{"scopes": []string{"read"}}, denco.Params{{Name: "id", Value: "alice"}}, true},
+		{"/about", "about", nil, nil, true},
+		{"/missing", nil, nil, nil, false},
+	} {
+		data, meta, params, found := r.LookupMeta(v.path)
+		if !reflect.DeepEqual(data, v.value) || !reflect.DeepEqual(meta, v.meta) || !reflect.DeepEqual(params, v.params) || found != v.found {
+			t.Errorf("Router.LookupMeta(%q) => (%#v, %#v, %#v, %#v), want (%#v, %#v, %#v, %#v)", v.path, data, meta, params, found, v.value, v.meta, v.params, v.found)
+		}
+	}
+}
@@ -1,7 +1,14 @@
 package denco
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 )
 
 // Mux represents a multiplexer for HTTP request.
@@ -9,6 +16,46 @@ type Mux struct {
 	// NotFound is the custom NotFound handler for this Mux.
 	// If nil, Denco will use 'denco.NotFound' handler.
 	NotFound HandlerFunc
+
+	// UseEscapedPath makes the Mux route on the escaped path of the request
+	// (URL.EscapedPath) instead of the unescaped path, and unescape the value of each
+	// path parameter individually. It allows the value of path parameter to contain
+	// an encoded "/" such as "a%2Fb". Note that routing paths must be written in
+	// escaped form if they contain characters that need to be escaped.
+	UseEscapedPath bool
+
+	// PanicHandler is called with the recovered value when a handler panics.
+	// If nil, the panic will not be recovered.
+	// http.ErrAbortHandler is not recovered regardless of PanicHandler.
+	PanicHandler func(w http.ResponseWriter, r *http.Request, v interface{})
+
+	// ErrorHandler is the custom error handler for the handlers that converted by Mux.E.
+	// If nil, Denco will use 'denco.ErrorHandler'.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+	// Tracer is the hook to trace requests.
+	// If nil, requests are not traced.
+	Tracer Tracer
+
+	// Timeout is the default time limit of handlers.
+	// The context of the request is canceled when the time limit is exceeded, and the client
+	// receives "503 Service Unavailable" if the handler hasn't returned. The response is
+	// buffered by http.TimeoutHandler, so that the handler cannot flush or hijack it.
+	// The handler runs in another goroutine with a copy of the Route of the request,
+	// see TrackRouteFunc.
+	// If zero, handlers have no time limit.
+	Timeout time.Duration
+
+	// MaxBodyBytes is the default maximum size of the request body.
+	// The request is replied with "413 Request Entity Too Large" if Content-Length exceeds it,
+	// otherwise reading the body beyond it fails. If zero, the size is unlimited.
+	MaxBodyBytes int64
+
+	// MaxHeaderBytes is the default maximum size of the request header.
+	// The request is replied with "431 Request Header Fields Too Large" if the header exceeds it.
+	// It is checked after the header is read, so http.Server.MaxHeaderBytes must be larger than it.
+	// If zero, the size is unlimited.
+	MaxHeaderBytes int
 }
 
 // NewMux returns a new Mux.
@@ -45,27 +92,232 @@ func (m *Mux) Handler(method, path string, handler HandlerFunc) Handler {
 	}
 }
 
+// Host returns handlers that set Host to host.
+// host is a host name such as "example.com", and may contain path parameters as labels
+// such as ":tenant.example.com". A wildcard parameter is allowed only as the leftmost label
+// such as "*subdomain.example.com".
+func (m *Mux) Host(host string, handlers ...Handler) []Handler {
+	result := make([]Handler, len(handlers))
+	for i, h := range handlers {
+		h.Host = host
+		result[i] = h
+	}
+	return result
+}
+
+// Wrap returns handlers that Func is wrapped by mw, e.g.
+//
+//	handlers := mux.Wrap(auth.Require(authenticator).Wrap,
+//		mux.GET("/admin/users", ListUsers),
+//		mux.POST("/admin/users", CreateUser),
+//	)
+//
+// See Handler.Wrap for details.
+func (m *Mux) Wrap(mw func(HandlerFunc) HandlerFunc, handlers ...Handler) []Handler {
+	result := make([]Handler, len(handlers))
+	for i, h := range handlers {
+		result[i] = h.Wrap(mw)
+	}
+	return result
+}
+
+// Mount returns handlers that delegate requests for path and under path to handler.
+// path must not contain path parameters, otherwise Mount panics. The path of the request is stripped of path
+// before it is passed to handler, e.g. the request for "/admin/users" is passed to handler
+// as "/users" when path is "/admin".
+// The handlers match any method.
+//
+// If handler is a http.Handler that built by Mux.Build and it has no NotFound handler,
+// the NotFound handler of m is used for requests that are not found in handler.
+// If handler is built by Mux.Build, Route.Path of the matched handler is joined to path,
+// e.g. "/admin/users/:id". Otherwise, Route.Path is path joined with "/*rest" that has
+// the rest of the path, e.g. "/admin/*rest".
+func (m *Mux) Mount(path string, handler http.Handler) []Handler {
+	if strings.ContainsAny(path, string([]byte{ParamCharacter, WildcardCharacter})) {
+		panic(fmt.Sprintf("denco: Mount path must not contain path parameters, but `%v' given", path))
+	}
+	prefix := strings.TrimSuffix(path, "/")
+	fn := func(w http.ResponseWriter, r *http.Request, params Params) {
+		r2 := stripPrefix(r, prefix)
+		if route := RouteFromContext(r.Context()); route != nil {
+			// Joins prefix to the path of the route that matched in handler,
+			// even if it is served through handler.ServeHTTP.
+			outer := route.prefix
+			route.prefix = outer + prefix
+			defer func() { route.prefix = outer }()
+		}
+		if sub, ok := handler.(*serveMux); ok && sub.NotFound == nil {
+			if h, params, found := sub.lookup(r2); found {
+				sub.serveFound(w, r2, h, params)
+				return
+			}
+			if m.NotFound != nil {
+				m.NotFound(w, r, nil)
+			} else {
+				NotFound(w, r, nil)
+			}
+			return
+		}
+		handler.ServeHTTP(w, r2)
+	}
+	handlers := []Handler{
+		m.Handler("", prefix+"/", fn),
+		m.Handler("", prefix+"/*"+mountParamName, fn),
+	}
+	if prefix != "" {
+		handlers = append(handlers, m.Handler("", prefix, fn))
+	}
+	return handlers
+}
+
+// mountParamName is a name of the wildcard parameter of handlers that returned by Mux.Mount.
+const mountParamName = "rest"
+
+// stripPrefix returns a shallow copy of r that prefix is stripped from the path.
+func stripPrefix(r *http.Request, prefix string) *http.Request {
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if r.URL.RawPath != "" {
+		if rp := strings.TrimPrefix(r.URL.RawPath, prefix); len(rp) < len(r.URL.RawPath) {
+			r2.URL.RawPath = "/" + strings.TrimPrefix(rp, "/")
+		} else {
+			r2.URL.RawPath = ""
+		}
+	}
+	return r2
+}
+
 // Build builds a http.Handler.
 func (m *Mux) Build(handlers []Handler) (http.Handler, error) {
-	recordMap := make(map[string][]Record)
+	hostMap := make(map[string]map[string]map[string][]Handler)
 	for _, h := range handlers {
-		recordMap[h.Method] = append(recordMap[h.Method], NewRecord(h.Path, h.Func))
+		methodMap := hostMap[h.Host]
+		if methodMap == nil {
+			methodMap = make(map[string]map[string][]Handler)
+			hostMap[h.Host] = methodMap
+		}
+		pathMap := methodMap[h.Method]
+		if pathMap == nil {
+			pathMap = make(map[string][]Handler)
+			methodMap[h.Method] = pathMap
+		}
+		pathMap[h.Path] = append(pathMap[h.Path], h)
 	}
 	mux := newServeMux()
-	for m, records := range recordMap {
-		router := New()
-		if err := router.Build(records); err != nil {
+	var hostRecords []Record
+	for host, methodMap := range hostMap {
+		routers, err := buildRouters(methodMap)
+		if err != nil {
+			return nil, err
+		}
+		if host == "" {
+			mux.routers = routers
+			continue
+		}
+		key, err := hostKey(host)
+		if err != nil {
+			return nil, err
+		}
+		hostRecords = append(hostRecords, NewRecord(key, routers))
+	}
+	if len(hostRecords) > 0 {
+		mux.hosts = New()
+		if err := mux.hosts.Build(hostRecords); err != nil {
 			return nil, err
 		}
-		mux.routers[m] = router
 	}
 	mux.NotFound = m.NotFound
+	mux.useEscapedPath = m.UseEscapedPath
+	mux.panicHandler = m.PanicHandler
+	mux.tracer = m.Tracer
+	mux.limits = limits{timeout: m.Timeout, maxBodyBytes: m.MaxBodyBytes, maxHeaderBytes: m.MaxHeaderBytes}
 	return mux, nil
 }
 
+// buildRouters returns Routers for each method.
+// The data of each record is a list of candidate handlers that share the same method and path.
+func buildRouters(methodMap map[string]map[string][]Handler) (map[string]*Router, error) {
+	routers := make(map[string]*Router)
+	for m, pathMap := range methodMap {
+		records := make([]Record, 0, len(pathMap))
+		for path, handlers := range pathMap {
+			records = append(records, NewRecord(path, candidates(handlers)))
+		}
+		router := New()
+		if err := router.Build(records); err != nil {
+			return nil, err
+		}
+		routers[m] = router
+	}
+	return routers, nil
+}
+
+// candidates returns handlers in the order of evaluation.
+// Handlers that have matchers are evaluated in the given order, and then the last handler
+// that has no matchers is evaluated as a fallback.
+func candidates(handlers []Handler) []Handler {
+	var (
+		result   []Handler
+		fallback *Handler
+	)
+	for i, h := range handlers {
+		if len(h.Matchers) == 0 {
+			fallback = &handlers[i]
+			continue
+		}
+		result = append(result, h)
+	}
+	if fallback != nil {
+		result = append(result, *fallback)
+	}
+	return result
+}
+
+// hostKey returns a key of the Router for host.
+// The labels of host are reversed and joined with "/" in order to match by the Router.
+// e.g. ":tenant.example.com" will be converted to "/com/example/:tenant".
+func hostKey(host string) (string, error) {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(host, ".")), ".")
+	for i, label := range labels {
+		if i > 0 && strings.IndexByte(label, WildcardCharacter) >= 0 {
+			return "", fmt.Errorf("denco: wildcard parameter must be the leftmost label of the host `%v'", host)
+		}
+		if strings.IndexByte(label, '/') >= 0 {
+			return "", fmt.Errorf("denco: invalid host `%v'", host)
+		}
+	}
+	reverseStrings(labels)
+	return "/" + strings.Join(labels, "/"), nil
+}
+
+// hostParams converts params that looked up by the key of hostKey into the order of labels of host.
+func hostParams(params Params) Params {
+	for i := range params {
+		if strings.IndexByte(params[i].Value, '/') >= 0 {
+			labels := strings.Split(params[i].Value, "/")
+			reverseStrings(labels)
+			params[i].Value = strings.Join(labels, ".")
+		}
+	}
+	for i, j := 0, len(params)-1; i < j; i, j = i+1, j-1 {
+		params[i], params[j] = params[j], params[i]
+	}
+	return params
+}
+
+func reverseStrings(a []string) {
+	for i, j := 0, len(a)-1; i < j; i, j = i+1, j-1 {
+		a[i], a[j] = a[j], a[i]
+	}
+}
+
 // Handler represents a handler of HTTP request.
 type Handler struct {
 	// Method is an HTTP method.
+	// If empty, handler will match any method that is not matched by other handlers.
 	Method string
 
 	// Path is a routing path for handler.
@@ -73,14 +325,80 @@ type Handler struct {
 
 	// Func is a function of handler of HTTP request.
 	Func HandlerFunc
+
+	// Host is a host name for handler.
+	// If empty, handler will match any host that is not matched by other handlers.
+	// See Mux.Host for details.
+	Host string
+
+	// Meta is arbitrary metadata of the route.
+	// It can be retrieved from the context of the request by RouteFromContext or MetaFromContext.
+	Meta Meta
+
+	// Matchers is a list of additional conditions of request.
+	// If there are multiple handlers for the same method and path, the handler that all of
+	// the matchers match the request will be used. See Handler.Match for details.
+	Matchers []Matcher
+
+	// Timeout is the time limit of the handler.
+	// If zero, Mux.Timeout is used. If negative, the handler has no time limit.
+	// See Mux.Timeout for details.
+	Timeout time.Duration
+
+	// MaxBodyBytes is the maximum size of the request body.
+	// If zero, Mux.MaxBodyBytes is used. If negative, the size is unlimited.
+	MaxBodyBytes int64
+
+	// MaxHeaderBytes is the maximum size of the request header.
+	// If zero, Mux.MaxHeaderBytes is used. If negative, the size is unlimited.
+	MaxHeaderBytes int
+}
+
+// Match returns a copy of the handler that matchers are appended to Matchers.
+// Handlers that have matchers are evaluated in the order given to Mux.Build.
+// A handler that has no matchers for the same method and path is used when none of them match.
+func (h Handler) Match(matchers ...Matcher) Handler {
+	h.Matchers = append(h.Matchers[:len(h.Matchers):len(h.Matchers)], matchers...)
+	return h
+}
+
+// Wrap returns a copy of the handler that Func is wrapped by mw.
+// mw is a middleware such as the Wrap method of ratelimit.Limiter, auth.Middleware
+// and etag.ETagger, or routefile.Middleware, e.g.
+//
+//	mux.GET("/jobs", ListJobs).Wrap(limiter.Wrap)
+func (h Handler) Wrap(mw func(HandlerFunc) HandlerFunc) Handler {
+	h.Func = mw(h.Func)
+	return h
+}
+
+// WithMeta returns a copy of the handler that value is associated with key in Meta.
+func (h Handler) WithMeta(key string, value interface{}) Handler {
+	meta := make(Meta, len(h.Meta)+1)
+	for k, v := range h.Meta {
+		meta[k] = v
+	}
+	meta[key] = value
+	h.Meta = meta
+	return h
 }
 
 // The HandlerFunc type is aliased to type of handler function.
 type HandlerFunc func(w http.ResponseWriter, r *http.Request, params Params)
 
 type serveMux struct {
-	routers  map[string]*Router
+	routers map[string]*Router
+
+	// hosts is a Router that has map[string]*Router for each host.
+	// hosts is nil if no handler has Host.
+	hosts *Router
+
 	NotFound HandlerFunc
+
+	useEscapedPath bool
+	panicHandler   func(w http.ResponseWriter, r *http.Request, v interface{})
+	tracer         Tracer
+	limits         limits
 }
 
 func newServeMux() *serveMux {
@@ -91,20 +409,195 @@ func newServeMux() *serveMux {
 
 // ServeHTTP implements http.Handler interface.
 func (mux *serveMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	handler, params := mux.handler(r.Method, r.URL.Path)
-	handler(w, r, params)
+	mux.wrap(w, r, mux.route)
+}
+
+// serveFound serves r by h that has been looked up by mux.
+// It is used by Mux.Mount to fall back to the NotFound handler of the parent Mux.
+func (mux *serveMux) serveFound(w http.ResponseWriter, r *http.Request, h *Handler, params Params) {
+	mux.wrap(w, r, func(w http.ResponseWriter, r *http.Request) {
+		mux.serveHandler(w, r, h, params)
+	})
+}
+
+// wrap calls serve with the tracer and the panic handler of mux.
+func (mux *serveMux) wrap(w http.ResponseWriter, r *http.Request, serve http.HandlerFunc) {
+	if mux.tracer != nil {
+		mux.serveTraced(w, r, serve)
+		return
+	}
+	mux.serve(w, r, serve)
 }
 
-func (mux *serveMux) handler(method, path string) (HandlerFunc, []Param) {
-	if router, found := mux.routers[method]; found {
-		if handler, params, found := router.Lookup(path); found {
-			return handler.(HandlerFunc), params
+func (mux *serveMux) serve(w http.ResponseWriter, r *http.Request, serve http.HandlerFunc) {
+	if mux.panicHandler != nil {
+		defer mux.recover(w, r)
+	}
+	serve(w, r)
+}
+
+// route serves r by the handler that matches r, or the NotFound handler.
+func (mux *serveMux) route(w http.ResponseWriter, r *http.Request) {
+	if h, params, found := mux.lookup(r); found {
+		mux.serveHandler(w, r, h, params)
+		return
+	}
+	mux.notFound()(w, r, nil)
+}
+
+// serveHandler calls the function of h with the Route of h in the context of r.
+func (mux *serveMux) serveHandler(w http.ResponseWriter, r *http.Request, h *Handler, params Params) {
+	route, _ := r.Context().Value(routeContextKey{}).(*Route)
+	if route == nil {
+		route = &Route{}
+		r = r.WithContext(context.WithValue(r.Context(), routeContextKey{}, route))
+	}
+	route.set(h, params)
+	mux.limits.serve(w, r, h, params)
+}
+
+func (mux *serveMux) recover(w http.ResponseWriter, r *http.Request) {
+	if v := recover(); v != nil {
+		if v == http.ErrAbortHandler {
+			panic(v)
+		}
+		mux.panicHandler(w, r, v)
+	}
+}
+
+// lookup returns a handler and path parameters for r.
+func (mux *serveMux) lookup(r *http.Request) (*Handler, Params, bool) {
+	if mux.hosts != nil {
+		if h, params, found := mux.hostHandler(r); found {
+			return h, params, true
 		}
 	}
+	return mux.lookupRouters(mux.routers, r)
+}
+
+func (mux *serveMux) notFound() HandlerFunc {
 	if mux.NotFound != nil {
-		return mux.NotFound, nil
+		return mux.NotFound
+	}
+	return NotFound
+}
+
+func (mux *serveMux) hostHandler(r *http.Request) (*Handler, Params, bool) {
+	key, err := hostKey(stripPort(r.Host))
+	if err != nil {
+		return nil, nil, false
+	}
+	routers, hparams, found := mux.hosts.Lookup(key)
+	if !found {
+		return nil, nil, false
+	}
+	h, params, found := mux.lookupRouters(routers.(map[string]*Router), r)
+	if !found {
+		return nil, nil, false
+	}
+	return h, append(hostParams(hparams), params...), true
+}
+
+// stripPort returns host without the port.
+func stripPort(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// lookupRouters returns a handler and path parameters for r from the Routers for each method.
+// The Router for the empty method is used if no handler is found for the method of r.
+func (mux *serveMux) lookupRouters(routers map[string]*Router, r *http.Request) (*Handler, Params, bool) {
+	path := r.URL.Path
+	if mux.useEscapedPath {
+		path = r.URL.EscapedPath()
+	}
+	for _, method := range [...]string{r.Method, ""} {
+		router, found := routers[method]
+		if !found {
+			continue
+		}
+		if handlers, params, found := router.Lookup(path); found {
+			if h := selectHandler(handlers.([]Handler), r); h != nil {
+				if mux.useEscapedPath {
+					unescapeParams(params)
+				}
+				return h, params, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// AllowedMethods returns the methods of the handlers that match the host and path of r
+// regardless of the method of r, in sorted order. handler must be a http.Handler built by Mux.Build,
+// otherwise AllowedMethods returns false. Matchers of handlers are not evaluated.
+// The handlers that match any method, such as the handlers returned by Mux.Mount, are reported as "*".
+func AllowedMethods(handler http.Handler, r *http.Request) ([]string, bool) {
+	mux, ok := handler.(*serveMux)
+	if !ok {
+		return nil, false
+	}
+	set := make(map[string]struct{})
+	if mux.hosts != nil {
+		if key, err := hostKey(stripPort(r.Host)); err == nil {
+			if routers, _, found := mux.hosts.Lookup(key); found {
+				mux.allowedMethods(routers.(map[string]*Router), r, set)
+			}
+		}
+	}
+	mux.allowedMethods(mux.routers, r, set)
+	methods := make([]string, 0, len(set))
+	for method := range set {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods, true
+}
+
+// allowedMethods adds the methods of routers that match the path of r to set.
+func (mux *serveMux) allowedMethods(routers map[string]*Router, r *http.Request, set map[string]struct{}) {
+	path := r.URL.Path
+	if mux.useEscapedPath {
+		path = r.URL.EscapedPath()
+	}
+	for method, router := range routers {
+		if _, _, found := router.Lookup(path); found {
+			if method == "" {
+				method = "*"
+			}
+			set[method] = struct{}{}
+		}
+	}
+}
+
+// unescapeParams unescapes the values of params in place.
+// The value that cannot be unescaped is left as is.
+func unescapeParams(params Params) {
+	for i := range params {
+		if strings.IndexByte(params[i].Value, '%') < 0 {
+			continue
+		}
+		if v, err := url.PathUnescape(params[i].Value); err == nil {
+			params[i].Value = v
+		}
+	}
+}
+
+// selectHandler returns the first handler that all of the matchers match r.
+// selectHandler returns nil if there is no such handler.
+func selectHandler(handlers []Handler, r *http.Request) *Handler {
+NEXT:
+	for i := range handlers {
+		for _, m := range handlers[i].Matchers {
+			if !m(r) {
+				continue NEXT
+			}
+		}
+		return &handlers[i]
 	}
-	return NotFound, nil
+	return nil
 }
 
 // NotFound replies to the request with an HTTP 404 not found error.
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/naoina/denco"
@@ -133,3 +134,288 @@ func TestCustomNotFound(t *testing.T) {
 		t.Errorf(`GET "/" => %#v %#v, want %#v %#v`, res.StatusCode, actual, http.StatusServiceUnavailable, expected)
 	}
 }
+
+func TestMux_Host(t *testing.T) {
+	mux := denco.NewMux()
+	handlers := []denco.Handler{
+		mux.GET("/", testHandlerFunc),
+		mux.GET("/user/:name", testHandlerFunc),
+	}
+	handlers = append(handlers, mux.Host("example.com",
+		mux.GET("/", testHandlerFunc),
+		mux.GET("/about", testHandlerFunc),
+	)...)
+	handlers = append(handlers, mux.Host(":tenant.:region.example.com",
+		mux.GET("/user/:name", testHandlerFunc),
+	)...)
+	handlers = append(handlers, mux.Host("*sub.example.org",
+		mux.GET("/", testHandlerFunc),
+	)...)
+	handler, err := mux.Build(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		status                       int
+		method, host, path, expected string
+	}{
+		{200, "GET", "example.com", "/about", "method: GET, path: /about, params: []"},
+		{200, "GET", "EXAMPLE.com:8080", "/about", "method: GET, path: /about, params: []"},
+		{404, "GET", "other.com", "/about", "404 page not found\n"},
+		{200, "GET", "other.com", "/user/alice", "method: GET, path: /user/alice, params: [{name alice}]"},
+		{200, "GET", "acme.eu.example.com", "/user/alice", "method: GET, path: /user/alice, params: [{tenant acme} {region eu} {name alice}]"},
+		{200, "GET", "acme.eu.example.com", "/", "method: GET, path: /, params: []"},
+		{404, "POST", "acme.eu.example.com", "/user/alice", "404 page not found\n"},
+		{200, "GET", "a.b.c.example.org", "/", "method: GET, path: /, params: [{sub a.b.c}]"},
+	} {
+		req := httptest.NewRequest(v.method, v.path, nil)
+		req.Host = v.host
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if actual := w.Body.String(); w.Code != v.status || actual != v.expected {
+			t.Errorf(`%s "%s%s" => %#v %#v, want %#v %#v`, v.method, v.host, v.path, w.Code, actual, v.status, v.expected)
+		}
+	}
+
+	if _, err := mux.Build(mux.Host("a.*sub.example.com", mux.GET("/", testHandlerFunc))); err == nil {
+		t.Errorf("Mux.Build with wildcard in the middle of the host => nil, want error")
+	}
+}
+
+func TestMux_Matchers(t *testing.T) {
+	mux := denco.NewMux()
+	handlerFunc := func(name string) denco.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			fmt.Fprintf(w, "%s: %v", name, params)
+		}
+	}
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/user/:name", handlerFunc("default")),
+		mux.GET("/user/:name", handlerFunc("v2")).Match(denco.MatchHeader("Accept", "application/vnd.v2+json")),
+		mux.GET("/user/:name", handlerFunc("debug")).Match(denco.MatchQuery("debug")),
+		mux.POST("/hook", handlerFunc("push")).Match(denco.MatchHeader("X-Event-Type", "push")),
+		mux.POST("/hook", handlerFunc("ping")).Match(denco.MatchHeader("X-Event-Type", "ping")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		status                 int
+		method, path, expected string
+		header                 map[string]string
+	}{
+		{200, "GET", "/user/alice", "default: [{name alice}]", nil},
+		{200, "GET", "/user/alice", "v2: [{name alice}]", map[string]string{"Accept": "application/vnd.v2+json"}},
+		{200, "GET", "/user/alice?debug", "debug: [{name alice}]", nil},
+		{200, "GET", "/user/alice?debug", "v2: [{name alice}]", map[string]string{"Accept": "application/vnd.v2+json"}},
+		{200, "POST", "/hook", "push: []", map[string]string{"X-Event-Type": "push"}},
+		{200, "POST", "/hook", "ping: []", map[string]string{"X-Event-Type": "ping"}},
+		{404, "POST", "/hook", "404 page not found\n", map[string]string{"X-Event-Type": "issues"}},
+	} {
+		req := httptest.NewRequest(v.method, v.path, nil)
+		for k, val := range v.header {
+			req.Header.Set(k, val)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if actual := w.Body.String(); w.Code != v.status || actual != v.expected {
+			t.Errorf(`%s "%s" %v => %#v %#v, want %#v %#v`, v.method, v.path, v.header, w.Code, actual, v.status, v.expected)
+		}
+	}
+}
+
+func TestMux_Mount(t *testing.T) {
+	sub := denco.NewMux()
+	subHandler, err := sub.Build([]denco.Handler{
+		sub.GET("/", testHandlerFunc),
+		sub.GET("/users/:name", testHandlerFunc),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileServer := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "method: %s, path: %s, rawpath: %s", r.Method, r.URL.Path, r.URL.RawPath)
+	})
+	mux := denco.NewMux()
+	mux.NotFound = func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, "not found: %s", r.URL.Path)
+	}
+	handlers := []denco.Handler{
+		mux.GET("/user/:name", testHandlerFunc),
+	}
+	handlers = append(handlers, mux.Mount("/admin", subHandler)...)
+	handlers = append(handlers, mux.Mount("/static/", fileServer)...)
+	handler, err := mux.Build(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		status                 int
+		method, path, expected string
+	}{
+		{200, "GET", "/user/alice", "method: GET, path: /user/alice, params: [{name alice}]"},
+		{200, "GET", "/admin", "method: GET, path: /, params: []"},
+		{200, "GET", "/admin/", "method: GET, path: /, params: []"},
+		{200, "GET", "/admin/users/bob", "method: GET, path: /users/bob, params: [{name bob}]"},
+		{404, "POST", "/admin/users/bob", "not found: /admin/users/bob"},
+		{404, "GET", "/admin/unknown", "not found: /admin/unknown"},
+		{404, "GET", "/administrator", "not found: /administrator"},
+		{200, "GET", "/static/css/a.css", "method: GET, path: /css/a.css, rawpath: "},
+		{200, "DELETE", "/static/a%2Fb.css", "method: DELETE, path: /a/b.css, rawpath: /a%2Fb.css"},
+		{200, "GET", "/static", "method: GET, path: /, rawpath: "},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(v.method, v.path, nil))
+		if actual := w.Body.String(); w.Code != v.status || actual != v.expected {
+			t.Errorf(`%s "%s" => %#v %#v, want %#v %#v`, v.method, v.path, w.Code, actual, v.status, v.expected)
+		}
+	}
+}
+
+func TestMux_Mount_withPathParameter(t *testing.T) {
+	for _, path := range []string{"/t/:tenant", "/static/*filepath"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Mount(%q) => no panic, want panic", path)
+				}
+			}()
+			denco.NewMux().Mount(path, http.NotFoundHandler())
+		}()
+	}
+}
+
+func TestMux_UseEscapedPath(t *testing.T) {
+	for _, v := range []struct {
+		useEscapedPath bool
+		status         int
+		path, expected string
+	}{
+		{false, 404, "/files/a%2Fb/raw", "404 page not found\n"},
+		{false, 404, "/files/a%23b/raw", "404 page not found\n"},
+		{true, 200, "/files/a%2Fb/raw", "method: GET, path: /files/a/b/raw, params: [{name a/b}]"},
+		{true, 200, "/files/a%23b%2Fc/raw", "method: GET, path: /files/a#b/c/raw, params: [{name a#b/c}]"},
+		{true, 200, "/files/caf%C3%A9/raw", "method: GET, path: /files/café/raw, params: [{name café}]"},
+		{true, 200, "/static/a%2Fb/c", "method: GET, path: /static/a/b/c, params: [{filepath a/b/c}]"},
+		{true, 200, "/a%20b", "method: GET, path: /a b, params: []"},
+	} {
+		mux := denco.NewMux()
+		mux.UseEscapedPath = v.useEscapedPath
+		handler, err := mux.Build([]denco.Handler{
+			mux.GET("/files/:name/raw", testHandlerFunc),
+			mux.GET("/static/*filepath", testHandlerFunc),
+			mux.GET("/a%20b", testHandlerFunc),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", v.path, nil))
+		if actual := w.Body.String(); w.Code != v.status || actual != v.expected {
+			t.Errorf(`UseEscapedPath=%v GET "%s" => %#v %#v, want %#v %#v`, v.useEscapedPath, v.path, w.Code, actual, v.status, v.expected)
+		}
+	}
+}
+
+func TestMux_Meta(t *testing.T) {
+	mux := denco.NewMux()
+	metaHandlerFunc := func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+		meta := denco.MetaFromContext(r.Context())
+		fmt.Fprintf(w, "scope: %v, owner: %v", meta.Get("scope"), meta.String("owner"))
+	}
+	h := mux.GET("/user/:name", metaHandlerFunc).WithMeta("scope", "users:read")
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/", metaHandlerFunc),
+		h,
+		h.WithMeta("owner", "team-a").Match(denco.MatchQuery("owned")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path, expected string
+	}{
+		{"/", "scope: <nil>, owner: "},
+		{"/user/alice", "scope: users:read, owner: "},
+		{"/user/alice?owned", "scope: users:read, owner: team-a"},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", v.path, nil))
+		if actual := w.Body.String(); actual != v.expected {
+			t.Errorf(`GET "%s" => %#v, want %#v`, v.path, actual, v.expected)
+		}
+	}
+	if len(h.Meta) != 1 {
+		t.Errorf("Handler.WithMeta modified the original Meta: %#v", h.Meta)
+	}
+}
+
+func TestMux_Wrap(t *testing.T) {
+	mux := denco.NewMux()
+	prefix := func(s string) func(denco.HandlerFunc) denco.HandlerFunc {
+		return func(fn denco.HandlerFunc) denco.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+				fmt.Fprint(w, s)
+				fn(w, r, params)
+			}
+		}
+	}
+	handlers := mux.Wrap(prefix("a:"),
+		mux.GET("/user/:id", testHandlerFunc),
+		mux.GET("/admin", testHandlerFunc).Wrap(prefix("b:")),
+	)
+	handler, err := mux.Build(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path, expected string
+	}{
+		{"/user/1", "a:method: GET, path: /user/1, params: [{id 1}]"},
+		{"/admin", "a:b:method: GET, path: /admin, params: []"},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", v.path, nil))
+		if actual := w.Body.String(); actual != v.expected {
+			t.Errorf(`GET "%s" => %#v, want %#v`, v.path, actual, v.expected)
+		}
+	}
+}
+
+func TestAllowedMethods(t *testing.T) {
+	mux := denco.NewMux()
+	handlerFunc := func(w http.ResponseWriter, r *http.Request, params denco.Params) {}
+	handlers := []denco.Handler{
+		mux.GET("/user/:id", handlerFunc),
+		mux.PUT("/user/:id", handlerFunc),
+		mux.Handler("DELETE", "/user/:id", handlerFunc),
+		mux.POST("/user", handlerFunc),
+	}
+	handlers = append(handlers, mux.Host("api.example.com", mux.Handler("PATCH", "/user/:id", handlerFunc))...)
+	handlers = append(handlers, mux.Mount("/admin", http.NotFoundHandler())...)
+	handler, err := mux.Build(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		host, path string
+		expected   []string
+	}{
+		{"example.com", "/user/1", []string{"DELETE", "GET", "PUT"}},
+		{"api.example.com:8080", "/user/1", []string{"DELETE", "GET", "PATCH", "PUT"}},
+		{"example.com", "/user", []string{"POST"}},
+		{"example.com", "/admin/users", []string{"*"}},
+		{"example.com", "/missing", []string{}},
+	} {
+		req := httptest.NewRequest("OPTIONS", v.path, nil)
+		req.Host = v.host
+		actual, ok := denco.AllowedMethods(handler, req)
+		if !ok || !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf(`AllowedMethods(handler, "%s%s") => %#v, %v, want %#v, true`, v.host, v.path, actual, ok, v.expected)
+		}
+	}
+	if _, ok := denco.AllowedMethods(http.NotFoundHandler(), httptest.NewRequest("GET", "/", nil)); ok {
+		t.Errorf("AllowedMethods(http.NotFoundHandler(), ...) => _, true, want _, false")
+	}
+}
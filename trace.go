@@ -0,0 +1,105 @@
+package denco
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/naoina/denco/internal/httputil"
+)
+
+// Attribute keys of SpanInfo.Attributes.
+// The keys of the route and the status code follow the OpenTelemetry semantic conventions for HTTP.
+const (
+	AttributeMethod      = "http.request.method"
+	AttributeRoute       = "http.route"
+	AttributeStatusCode  = "http.response.status_code"
+	AttributeParamPrefix = "denco.param."
+)
+
+// Tracer is the hook to trace requests that are served by the http.Handler built by Mux.Build.
+// It is designed to be adapted to a tracing library such as OpenTelemetry.
+type Tracer interface {
+	// StartSpan is called when a request arrives, before routing.
+	// The returned context is used as the context of the request, so that
+	// the handler can create child spans from it.
+	StartSpan(ctx context.Context, r *http.Request) (context.Context, Span)
+}
+
+// Span represents a span that is started by Tracer.
+type Span interface {
+	// End is called after the handler returns.
+	End(info SpanInfo)
+}
+
+// SpanInfo represents the result of a request that is traced.
+type SpanInfo struct {
+	// Method is the method of the request.
+	Method string
+
+	// Route is the route that matched the request.
+	// Route.Path is empty if no handler matched.
+	Route Route
+
+	// Params is the path parameters of the matched route.
+	Params Params
+
+	// StatusCode is the status code of the response.
+	// It is zero if the handler panicked before writing the response.
+	StatusCode int
+
+	// Panicked reports whether the handler panicked and the panic wasn't recovered by Mux.PanicHandler.
+	Panicked bool
+}
+
+// Name returns a span name such as "GET /user/:id".
+// Name returns the method only if no handler matched.
+func (info SpanInfo) Name() string {
+	if info.Route.Path == "" {
+		return info.Method
+	}
+	return info.Method + " " + info.Route.Path
+}
+
+// SpanAttribute represents a key-value pair of an attribute of a span.
+type SpanAttribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attributes returns attributes of the span.
+// The value of each path parameter is added with the key of AttributeParamPrefix + name.
+func (info SpanInfo) Attributes() []SpanAttribute {
+	attrs := make([]SpanAttribute, 0, 3+len(info.Params))
+	attrs = append(attrs, SpanAttribute{AttributeMethod, info.Method})
+	if info.Route.Path != "" {
+		attrs = append(attrs, SpanAttribute{AttributeRoute, info.Route.Path})
+	}
+	attrs = append(attrs, SpanAttribute{AttributeStatusCode, info.StatusCode})
+	for _, param := range info.Params {
+		attrs = append(attrs, SpanAttribute{AttributeParamPrefix + param.Name, param.Value})
+	}
+	return attrs
+}
+
+// serveTraced calls serve in a span that is started by the tracer.
+func (mux *serveMux) serveTraced(w http.ResponseWriter, r *http.Request, serve http.HandlerFunc) {
+	ctx, span := mux.tracer.StartSpan(r.Context(), r)
+	r, route := TrackRoute(r.WithContext(ctx))
+	rw := httputil.NewResponseWriter(w)
+	panicked := true
+	defer func() {
+		info := SpanInfo{
+			Method:     r.Method,
+			Route:      *route,
+			Params:     route.Params,
+			StatusCode: rw.StatusCode(),
+			Panicked:   panicked,
+		}
+		if panicked {
+			info.StatusCode = rw.Status
+		}
+		span.End(info)
+	}()
+	mux.serve(rw, r, serve)
+	panicked = false
+}
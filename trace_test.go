@@ -0,0 +1,120 @@
+package denco_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/naoina/denco"
+)
+
+type testTracer struct {
+	started []string
+	ended   []denco.SpanInfo
+}
+
+type testSpan struct {
+	tracer *testTracer
+}
+
+type testSpanKey struct{}
+
+func (t *testTracer) StartSpan(ctx context.Context, r *http.Request) (context.Context, denco.Span) {
+	t.started = append(t.started, r.URL.Path)
+	return context.WithValue(ctx, testSpanKey{}, r.URL.Path), &testSpan{tracer: t}
+}
+
+func (s *testSpan) End(info denco.SpanInfo) {
+	s.tracer.ended = append(s.tracer.ended, info)
+}
+
+func TestMux_Tracer(t *testing.T) {
+	tracer := &testTracer{}
+	mux := denco.NewMux()
+	mux.Tracer = tracer
+	var spanValue interface{}
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/user/:id", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			spanValue = r.Context().Value(testSpanKey{})
+			w.WriteHeader(http.StatusAccepted)
+		}),
+		mux.GET("/panic", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			panic("test")
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/user/naoina", "/missing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expect panic, but not")
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+	}()
+	if expected := "/user/naoina"; !reflect.DeepEqual(spanValue, expected) {
+		t.Errorf("context value => %#v, want %#v", spanValue, expected)
+	}
+	expected := []denco.SpanInfo{
+		{
+			Method:     "GET",
+			Route:      denco.Route{Method: "GET", Path: "/user/:id", Params: denco.Params{{Name: "id", Value: "naoina"}}},
+			Params:     denco.Params{{Name: "id", Value: "naoina"}},
+			StatusCode: http.StatusAccepted,
+		},
+		{Method: "GET", StatusCode: http.StatusNotFound},
+		{
+			Method:   "GET",
+			Route:    denco.Route{Method: "GET", Path: "/panic"},
+			Panicked: true,
+		},
+	}
+	if !reflect.DeepEqual(tracer.ended, expected) {
+		t.Errorf("ended spans => %#v, want %#v", tracer.ended, expected)
+	}
+}
+
+func TestSpanInfo(t *testing.T) {
+	for _, v := range []struct {
+		info          denco.SpanInfo
+		name          string
+		expectedAttrs []denco.SpanAttribute
+	}{
+		{
+			denco.SpanInfo{
+				Method:     "GET",
+				Route:      denco.Route{Method: "GET", Path: "/user/:id"},
+				Params:     denco.Params{{Name: "id", Value: "naoina"}},
+				StatusCode: 200,
+			},
+			"GET /user/:id",
+			[]denco.SpanAttribute{
+				{Key: "http.request.method", Value: "GET"},
+				{Key: "http.route", Value: "/user/:id"},
+				{Key: "http.response.status_code", Value: 200},
+				{Key: "denco.param.id", Value: "naoina"},
+			},
+		},
+		{
+			denco.SpanInfo{Method: "POST", StatusCode: 404},
+			"POST",
+			[]denco.SpanAttribute{
+				{Key: "http.request.method", Value: "POST"},
+				{Key: "http.response.status_code", Value: 404},
+			},
+		},
+	} {
+		if actual := v.info.Name(); actual != v.name {
+			t.Errorf("%#v.Name() => %#v, want %#v", v.info, actual, v.name)
+		}
+		if actual := v.info.Attributes(); !reflect.DeepEqual(actual, v.expectedAttrs) {
+			t.Errorf("%#v.Attributes() => %#v, want %#v", v.info, actual, v.expectedAttrs)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/naoina/denco"
+)
+
+// RecordedSpan is a span that is recorded by Recorder.
+type RecordedSpan struct {
+	// Name is the span name such as "GET /user/:id".
+	Name string
+
+	// SpanContext is the context of this span.
+	SpanContext SpanContext
+
+	// Parent is the ID of the parent span propagated by the traceparent header.
+	// It is invalid if this span is a root span.
+	Parent SpanID
+
+	StartTime time.Time
+	EndTime   time.Time
+
+	// Attributes is the attributes of the span. See denco.SpanInfo.Attributes.
+	Attributes map[string]interface{}
+
+	// Panicked reports whether the handler panicked.
+	Panicked bool
+}
+
+// Recorder is a denco.Tracer that records ended spans in memory.
+// It is intended for testing and debugging.
+// The context of the request has the SpanContext of the current span, see SpanContextFromContext.
+type Recorder struct {
+	mu    sync.Mutex
+	spans []RecordedSpan
+	now   func() time.Time
+}
+
+// NewRecorder returns a new Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{now: time.Now}
+}
+
+// StartSpan implements denco.Tracer.
+func (rec *Recorder) StartSpan(ctx context.Context, r *http.Request) (context.Context, denco.Span) {
+	span := &recorderSpan{rec: rec}
+	span.StartTime = rec.now()
+	sc, ok := Extract(r.Header)
+	if ok {
+		span.Parent = sc.SpanID
+	} else {
+		sc = SpanContext{TraceID: newTraceID(), TraceFlags: FlagSampled}
+	}
+	sc.SpanID = newSpanID()
+	span.SpanContext = sc
+	return ContextWithSpanContext(ctx, sc), span
+}
+
+// Spans returns the ended spans in the order of ending.
+func (rec *Recorder) Spans() []RecordedSpan {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]RecordedSpan(nil), rec.spans...)
+}
+
+// Reset discards the recorded spans.
+func (rec *Recorder) Reset() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.spans = nil
+}
+
+type recorderSpan struct {
+	RecordedSpan
+	rec *Recorder
+}
+
+// End implements denco.Span.
+func (span *recorderSpan) End(info denco.SpanInfo) {
+	span.EndTime = span.rec.now()
+	span.Name = info.Name()
+	span.Panicked = info.Panicked
+	span.Attributes = make(map[string]interface{})
+	for _, attr := range info.Attributes() {
+		span.Attributes[attr.Key] = attr.Value
+	}
+	span.rec.mu.Lock()
+	defer span.rec.mu.Unlock()
+	span.rec.spans = append(span.rec.spans, span.RecordedSpan)
+}
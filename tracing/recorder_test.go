@@ -0,0 +1,98 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/naoina/denco"
+	"github.com/naoina/denco/tracing"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := tracing.NewRecorder()
+	mux := denco.NewMux()
+	mux.Tracer = rec
+	var outgoing http.Header
+	handler, err := mux.Build([]denco.Handler{
+		mux.GET("/user/:id", func(w http.ResponseWriter, r *http.Request, params denco.Params) {
+			sc, _ := tracing.SpanContextFromContext(r.Context())
+			outgoing = http.Header{}
+			tracing.Inject(outgoing, sc)
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("GET", "/user/naoina", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+
+	spans := rec.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("len(Spans()) => %v, want 2", len(spans))
+	}
+	span := spans[0]
+	if actual, expected := span.Name, "GET /user/:id"; actual != expected {
+		t.Errorf("Name => %q, want %q", actual, expected)
+	}
+	if actual, expected := span.SpanContext.TraceID.String(), "4bf92f3577b34da6a3ce929d0e0e4736"; actual != expected {
+		t.Errorf("TraceID => %q, want %q", actual, expected)
+	}
+	if actual, expected := span.Parent.String(), "00f067aa0ba902b7"; actual != expected {
+		t.Errorf("Parent => %q, want %q", actual, expected)
+	}
+	if actual, expected := outgoing.Get("traceparent"), span.SpanContext.Traceparent(); actual != expected {
+		t.Errorf("outgoing traceparent => %q, want %q", actual, expected)
+	}
+	expectedAttrs := map[string]interface{}{
+		"http.request.method":       "GET",
+		"http.route":                "/user/:id",
+		"http.response.status_code": 200,
+		"denco.param.id":            "naoina",
+	}
+	if !reflect.DeepEqual(span.Attributes, expectedAttrs) {
+		t.Errorf("Attributes => %#v, want %#v", span.Attributes, expectedAttrs)
+	}
+
+	span = spans[1]
+	if span.Name != "GET" || span.Parent.IsValid() || !span.SpanContext.IsValid() || !span.SpanContext.IsSampled() {
+		t.Errorf("root span => %#v", span)
+	}
+	if actual, expected := span.Attributes["http.response.status_code"], 404; actual != expected {
+		t.Errorf("status code => %v, want %v", actual, expected)
+	}
+
+	rec.Reset()
+	if spans := rec.Spans(); len(spans) != 0 {
+		t.Errorf("Spans() after Reset => %#v, want empty", spans)
+	}
+}
+
+func TestRecorder_Mount(t *testing.T) {
+	rec := tracing.NewRecorder()
+	sub := denco.NewMux()
+	sub.Tracer = rec
+	subHandler, err := sub.Build([]denco.Handler{
+		sub.GET("/user/:id", func(w http.ResponseWriter, r *http.Request, params denco.Params) {}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := denco.NewMux()
+	handler, err := mux.Build(mux.Mount("/admin", subHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/admin/user/naoina", nil))
+
+	spans := rec.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("len(Spans()) => %v, want 1", len(spans))
+	}
+	if actual, expected := spans[0].Name, "GET /admin/user/:id"; actual != expected {
+		t.Errorf("Name => %q, want %q", actual, expected)
+	}
+}
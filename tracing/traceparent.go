@@ -0,0 +1,154 @@
+// Package tracing provides W3C Trace Context propagation and an in-memory
+// implementation of denco.Tracer.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+)
+
+// Header names of W3C Trace Context.
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// FlagSampled is the sampled flag of TraceFlags.
+const FlagSampled byte = 0x01
+
+// TraceID is an identifier of a trace.
+type TraceID [16]byte
+
+// String returns the lowercase hex encoding of id.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether id is not all zeros.
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// SpanID is an identifier of a span.
+type SpanID [8]byte
+
+// String returns the lowercase hex encoding of id.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether id is not all zeros.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext represents the propagated context of a span.
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	TraceFlags byte
+
+	// TraceState is the value of the tracestate header that is passed through as is.
+	TraceState string
+}
+
+// IsValid reports whether both of TraceID and SpanID are valid.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// IsSampled reports whether the sampled flag is set.
+func (sc SpanContext) IsSampled() bool {
+	return sc.TraceFlags&FlagSampled != 0
+}
+
+// Traceparent returns the value of traceparent header such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, sc.TraceFlags)
+}
+
+// ParseTraceparent parses the value of traceparent header.
+// Unknown future versions are accepted as long as the known fields can be parsed.
+func ParseTraceparent(s string) (SpanContext, error) {
+	var sc SpanContext
+	if len(s) < 55 || (len(s) > 55 && s[55] != '-') || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return sc, fmt.Errorf("tracing: malformed traceparent `%v'", s)
+	}
+	var version [1]byte
+	if err := decodeHex(version[:], s[0:2]); err != nil || version[0] == 0xff || (version[0] == 0 && len(s) != 55) {
+		return sc, fmt.Errorf("tracing: invalid version of traceparent `%v'", s)
+	}
+	var flags [1]byte
+	if decodeHex(sc.TraceID[:], s[3:35]) != nil || decodeHex(sc.SpanID[:], s[36:52]) != nil || decodeHex(flags[:], s[53:55]) != nil {
+		return SpanContext{}, fmt.Errorf("tracing: malformed traceparent `%v'", s)
+	}
+	sc.TraceFlags = flags[0]
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("tracing: invalid trace-id or parent-id of traceparent `%v'", s)
+	}
+	return sc, nil
+}
+
+// decodeHex decodes lowercase hex encoded s into dst.
+func decodeHex(dst []byte, s string) error {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return fmt.Errorf("invalid character %q", c)
+		}
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+// Extract returns the SpanContext propagated by the traceparent and tracestate headers of h.
+// Extract returns false if h has no valid traceparent header.
+func Extract(h http.Header) (SpanContext, bool) {
+	sc, err := ParseTraceparent(h.Get(TraceparentHeader))
+	if err != nil {
+		return SpanContext{}, false
+	}
+	sc.TraceState = h.Get(TracestateHeader)
+	return sc, true
+}
+
+// Inject sets the traceparent and tracestate headers of sc to h.
+// Inject does nothing if sc is invalid.
+func Inject(h http.Header, sc SpanContext) {
+	if !sc.IsValid() {
+		return
+	}
+	h.Set(TraceparentHeader, sc.Traceparent())
+	if sc.TraceState != "" {
+		h.Set(TracestateHeader, sc.TraceState)
+	} else {
+		h.Del(TracestateHeader)
+	}
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a copy of ctx that has sc.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the SpanContext of the current span in ctx.
+// It can be passed to Inject to propagate the trace to outgoing requests.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok
+}
+
+func newTraceID() (id TraceID) {
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() (id SpanID) {
+	rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,59 @@
+package tracing_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/naoina/denco/tracing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	traceID := tracing.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	spanID := tracing.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	for _, v := range []struct {
+		s        string
+		expected tracing.SpanContext
+		valid    bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tracing.SpanContext{TraceID: traceID, SpanID: spanID, TraceFlags: 1}, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", tracing.SpanContext{TraceID: traceID, SpanID: spanID}, true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", tracing.SpanContext{TraceID: traceID, SpanID: spanID, TraceFlags: 1}, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", tracing.SpanContext{}, false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tracing.SpanContext{}, false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", tracing.SpanContext{}, false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", tracing.SpanContext{}, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", tracing.SpanContext{}, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", tracing.SpanContext{}, false},
+		{"", tracing.SpanContext{}, false},
+	} {
+		actual, err := tracing.ParseTraceparent(v.s)
+		if (err == nil) != v.valid {
+			t.Errorf("ParseTraceparent(%q) => error %v, want valid %v", v.s, err, v.valid)
+			continue
+		}
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("ParseTraceparent(%q) => %#v, want %#v", v.s, actual, v.expected)
+		}
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	sc, err := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc.TraceState = "vendor=value"
+	h := http.Header{}
+	tracing.Inject(h, sc)
+	if actual, expected := h.Get("traceparent"), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"; actual != expected {
+		t.Errorf("traceparent => %q, want %q", actual, expected)
+	}
+	actual, ok := tracing.Extract(h)
+	if !ok || !reflect.DeepEqual(actual, sc) {
+		t.Errorf("Extract(%v) => %#v, %v, want %#v, true", h, actual, ok, sc)
+	}
+	if _, ok := tracing.Extract(http.Header{}); ok {
+		t.Errorf("Extract of empty header => ok, want not ok")
+	}
+}
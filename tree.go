@@ -0,0 +1,173 @@
+package denco
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteTree writes a human readable representation of the routing table to w.
+// Static routes are kept outside of the Double-Array, so they are listed first.
+// The Double-Array is rendered as a trie that runs of static characters are merged into a single edge,
+// and branches of ParamCharacter and WildcardCharacter represent path parameters.
+// Leaves are annotated with data and names of path parameters.
+func (rt *Router) WriteTree(w io.Writer) error {
+	return rt.writeTree(w, nodeData)
+}
+
+// writeTree writes the routing table to w as WriteTree. value returns the value of a leaf to write.
+func (rt *Router) writeTree(w io.Writer, value func(nd *node) interface{}) error {
+	bw := bufio.NewWriter(w)
+	for _, key := range rt.staticKeys() {
+		fmt.Fprintf(bw, "%s => %v\n", key, value(rt.static[key]))
+	}
+	if root := rt.tree(); root != nil {
+		writeTreeNode(bw, root, 0, value)
+	}
+	return bw.Flush()
+}
+
+// WriteDOT writes the routing table to w as a graph of Graphviz DOT language.
+// The structure of the graph is the same as WriteTree.
+func (rt *Router) WriteDOT(w io.Writer) error {
+	return rt.writeDOT(w, nodeData)
+}
+
+// writeDOT writes the routing table to w as WriteDOT. value returns the value of a leaf to write.
+func (rt *Router) writeDOT(w io.Writer, value func(nd *node) interface{}) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph denco {")
+	fmt.Fprintln(bw, "\tnode [shape=ellipse];")
+	fmt.Fprintln(bw, "\tn0 [label=\"\", shape=point];")
+	id := 1
+	for _, key := range rt.staticKeys() {
+		fmt.Fprintf(bw, "\tn%d [label=\"%s\", shape=box];\n", id, dotEscape(fmt.Sprintf("%v", value(rt.static[key]))))
+		fmt.Fprintf(bw, "\tn0 -> n%d [label=\"%s\", style=dashed];\n", id, dotEscape(key))
+		id++
+	}
+	if root := rt.tree(); root != nil {
+		writeDOTNode(bw, root, 0, &id, value)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// nodeData returns the data of nd.
+func nodeData(nd *node) interface{} {
+	return nd.data
+}
+
+func (rt *Router) staticKeys() []string {
+	keys := make([]string, 0, len(rt.static))
+	for key := range rt.static {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tree returns a root of the trie that built from the Double-Array.
+// tree returns nil if the Double-Array is empty.
+func (rt *Router) tree() *treeNode {
+	if len(rt.param.node) == 1 {
+		return nil
+	}
+	return rt.param.tree(1, "")
+}
+
+// treeNode represents a node of the trie for visualization.
+type treeNode struct {
+	// Characters of the edge to this node.
+	label string
+
+	// A leaf data. nil if this node isn't a leaf.
+	leaf *node
+
+	children []*treeNode
+}
+
+// tree returns a trie that rooted at idx.
+func (da *doubleArray) tree(idx int, label string) *treeNode {
+	t := &treeNode{label: label}
+	for _, c := range da.childChars(idx) {
+		next := nextIndex(da.bc[idx].Base(), c)
+		switch c {
+		case da.termChar:
+			t.leaf = da.node[da.bc[next].Base()]
+		case da.wildcardChar:
+			t.children = append(t.children, &treeNode{
+				label: string(c),
+				leaf:  da.node[da.bc[next].Base()],
+			})
+		default:
+			t.children = append(t.children, da.tree(next, string(c)))
+		}
+	}
+	if t.leaf == nil && len(t.children) == 1 && !da.isSpecialLabel(t.label) && !da.isSpecialLabel(t.children[0].label) {
+		child := t.children[0]
+		child.label = t.label + child.label
+		return child
+	}
+	return t
+}
+
+// childChars returns characters of children of the node at idx in ascending order.
+func (da *doubleArray) childChars(idx int) []byte {
+	var chars []byte
+	base := da.bc[idx].Base()
+	for c := 1; c <= 0xff; c++ {
+		if next := nextIndex(base, byte(c)); next < len(da.bc) && da.bc[next].Check() == byte(c) {
+			chars = append(chars, byte(c))
+		}
+	}
+	return chars
+}
+
+func (da *doubleArray) isSpecialLabel(label string) bool {
+	return label == string(da.paramChar) || label == string(da.wildcardChar)
+}
+
+func writeTreeNode(w io.Writer, t *treeNode, depth int, value func(nd *node) interface{}) {
+	if t.label != "" {
+		fmt.Fprintf(w, "%s%s\n", strings.Repeat("  ", depth), treeNodeLabel(t, " => ", value))
+		depth++
+	}
+	for _, child := range t.children {
+		writeTreeNode(w, child, depth, value)
+	}
+}
+
+func writeDOTNode(w io.Writer, t *treeNode, parent int, id *int, value func(nd *node) interface{}) {
+	n := *id
+	*id++
+	shape := "ellipse"
+	if t.leaf != nil {
+		shape = "box"
+	}
+	fmt.Fprintf(w, "\tn%d [label=\"%s\", shape=%s];\n", n, dotEscape(treeNodeLabel(t, "\n", value)), shape)
+	fmt.Fprintf(w, "\tn%d -> n%d;\n", parent, n)
+	for _, child := range t.children {
+		writeDOTNode(w, child, n, id, value)
+	}
+}
+
+// treeNodeLabel returns a label of t.
+// If t is a leaf, the value and names of path parameters are appended to the label with sep.
+func treeNodeLabel(t *treeNode, sep string, value func(nd *node) interface{}) string {
+	if t.leaf == nil {
+		return t.label
+	}
+	label := fmt.Sprintf("%s%s%v", t.label, sep, value(t.leaf))
+	if len(t.leaf.paramNames) > 0 {
+		label += fmt.Sprintf(" [%s]", strings.Join(t.leaf.paramNames, ", "))
+	}
+	return label
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotEscape(s string) string {
+	return dotEscaper.Replace(s)
+}
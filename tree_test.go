@@ -0,0 +1,76 @@
+package denco_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/naoina/denco"
+)
+
+func TestRouter_WriteTree(t *testing.T) {
+	r := denco.New()
+	if err := r.Build([]denco.Record{
+		{Key: "/", Value: "root"},
+		{Key: "/user/:id", Value: "user"},
+		{Key: "/user/:name/:id", Value: "username"},
+		{Key: "/user/:name/posts", Value: "posts"},
+		{Key: "/static/*filepath", Value: "static"},
+		{Key: "/about", Value: "about"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := r.WriteTree(&buf); err != nil {
+		t.Fatal(err)
+	}
+	actual := buf.String()
+	expected := strings.Join([]string{
+		"/ => root",
+		"/about => about",
+		"/",
+		"  static/",
+		"    * => static [filepath]",
+		"  user/",
+		"    : => user [id]",
+		"      /",
+		"        : => username [name, id]",
+		"        posts => posts [name]",
+		"",
+	}, "\n")
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Router.WriteTree() => %q, want %q", actual, expected)
+	}
+}
+
+func TestRouter_WriteDOT(t *testing.T) {
+	r := denco.New()
+	if err := r.Build([]denco.Record{
+		{Key: "/", Value: "root"},
+		{Key: "/user/:id", Value: `"user"`},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := r.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	actual := buf.String()
+	expected := strings.Join([]string{
+		"digraph denco {",
+		"\tnode [shape=ellipse];",
+		"\tn0 [label=\"\", shape=point];",
+		"\tn1 [label=\"root\", shape=box];",
+		"\tn0 -> n1 [label=\"/\", style=dashed];",
+		"\tn2 [label=\"/user/\", shape=ellipse];",
+		"\tn0 -> n2;",
+		"\tn3 [label=\":\\n\\\"user\\\" [id]\", shape=box];",
+		"\tn2 -> n3;",
+		"}",
+		"",
+	}, "\n")
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Router.WriteDOT() => %q, want %q", actual, expected)
+	}
+}
@@ -2,8 +2,13 @@ package denco
 
 // NextSeparator returns an index of next separator in path.
 func NextSeparator(path string, start int) int {
+	return nextSeparator(path, start, '/', TerminationCharacter)
+}
+
+// nextSeparator returns an index of next sep or term in path.
+func nextSeparator(path string, start int, sep, term byte) int {
 	for start < len(path) {
-		if c := path[start]; c == '/' || c == TerminationCharacter {
+		if c := path[start]; c == sep || c == term {
 			break
 		}
 		start++